@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"github.com/sku4/mslu-parser/internal/repository"
+	"github.com/sku4/mslu-parser/internal/service"
+	"github.com/sku4/mslu-parser/pkg/logger"
+	"os"
+)
+
+func analyze(ctx context.Context, arguments []string) {
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+	repositoryConfig := repositoryFlags(fs)
+	loggerConfig := loggerFlags(fs)
+	_ = fs.Parse(arguments)
+	initLogger(loggerConfig)
+
+	log := logger.Get()
+	repos, err := repository.NewRepository(*repositoryConfig)
+	if err != nil {
+		log.Errorf("error init repository: %s", err.Error())
+		return
+	}
+	services := service.NewService(repos)
+
+	// interrupted or failed analysis exits non-zero after analyzed batches are saved
+	err = services.Analyzer.AnalyzeAll(ctx)
+	if err != nil {
+		log.Errorf("error analyze: %s", err.Error())
+	}
+	if closeErr := repos.Excel.Close(); closeErr != nil {
+		log.Errorf("error repository close: %s", closeErr.Error())
+		err = closeErr
+	}
+	if err != nil {
+		os.Exit(1)
+	}
+}
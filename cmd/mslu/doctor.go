@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/sku4/mslu-parser/internal/repository"
+	"github.com/sku4/mslu-parser/internal/service"
+	"github.com/sku4/mslu-parser/models/cli"
+	"github.com/sku4/mslu-parser/pkg/logger"
+	"os"
+)
+
+func doctor(ctx context.Context, arguments []string) {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(fs.Output(), "Usage: mslu doctor <profile> [flags]")
+		fs.PrintDefaults()
+	}
+	args := cli.Arguments{}
+	fs.IntVar(&args.Count, "count", 10, "Count sample articles")
+	profileFlags(fs, &args)
+	loggerConfig := loggerFlags(fs)
+	if len(arguments) == 0 || arguments[0] == "" || arguments[0][0] == '-' {
+		fs.Usage()
+		os.Exit(2)
+	}
+	args.Profile = arguments[0]
+	_ = fs.Parse(arguments[1:])
+	if args.Count < 1 {
+		_, _ = fmt.Fprintln(fs.Output(), "count must be at least 1")
+		fs.Usage()
+		os.Exit(2)
+	}
+	initLogger(loggerConfig)
+	ctx = cli.SetArgs(ctx, args)
+	ctx = logger.WithContext(ctx, "profile", args.Profile)
+
+	log := logger.FromContext(ctx)
+	// doctor does not read or write articles
+	services := service.NewService(&repository.Repository{})
+	if err := services.Parser.Doctor(ctx, os.Stdout); err != nil {
+		log.Errorf("error doctor: %s", err.Error())
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"github.com/sku4/mslu-parser/internal/repository"
+	"github.com/sku4/mslu-parser/internal/service"
+	"github.com/sku4/mslu-parser/models/cli"
+	"github.com/sku4/mslu-parser/pkg/logger"
+	"os"
+)
+
+func export(ctx context.Context, arguments []string) {
+	args := cli.ExportArguments{}
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	fs.StringVar(&args.Format, "format", "jsonl", "Available: jsonl, csv, tei, conllu, vertical")
+	fs.StringVar(&args.Output, "output", "", "Output file (stdout if empty), directory with -split")
+	fs.StringVar(&args.Match, "match", "", "Export only articles containing phrase")
+	fs.StringVar(&args.MatchFields, "match_fields", "", "Fields searched by -match (all text fields if empty)")
+	fs.BoolVar(&args.Split, "split", false, "Write one file per article into output directory")
+	fs.StringVar(&args.Profile, "profile", "", "Filter by profile: zeit, spiegel")
+	fs.StringVar(&args.From, "from", "", "Filter by date from (YYYY-MM-DD)")
+	fs.StringVar(&args.To, "to", "", "Filter by date to inclusive (YYYY-MM-DD)")
+	fs.BoolVar(&args.Unique, "unique", false, "Exclude near-duplicates of earlier articles")
+	fs.StringVar(&args.Fields, "fields", "", "Fields to export (url,profile,date,author,title,overtitle,lead,subtitles,imagetitles,analysis)")
+	fs.StringVar(&args.Joiner, "joiner", "|", "CSV list fields joiner")
+	repositoryConfig := repositoryFlags(fs)
+	loggerConfig := loggerFlags(fs)
+	_ = fs.Parse(arguments)
+	initLogger(loggerConfig)
+	ctx = cli.SetExportArgs(ctx, args)
+
+	log := logger.Get()
+	repos, err := repository.NewRepository(*repositoryConfig)
+	if err != nil {
+		log.Fatalf("error init repository: %s", err.Error())
+	}
+	services := service.NewService(repos)
+
+	// failed export exits non-zero after the repository is closed
+	output := args.Output
+	if args.Split {
+		output = ""
+	}
+	w, err := createOutput(output)
+	if err != nil {
+		log.Errorf("error create output: %s", err.Error())
+	} else {
+		err = services.Exporter.Export(ctx, w)
+		if closeErr := w.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			log.Errorf("error export: %s", err.Error())
+		}
+	}
+	if closeErr := repos.Excel.Close(); closeErr != nil {
+		log.Errorf("error repository close: %s", closeErr.Error())
+		err = closeErr
+	}
+	if err != nil {
+		os.Exit(1)
+	}
+	log.Infof("Export %s finished", args.Format)
+}
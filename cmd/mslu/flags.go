@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"github.com/sku4/mslu-parser/models/cli"
+	"time"
+)
+
+// crawlFlags registers flags of commands downloading and saving articles
+func crawlFlags(fs *flag.FlagSet, args *cli.Arguments) {
+	fs.StringVar(&args.Language, "language", "keep",
+		"Non-german articles: keep, drop, separate (saved under profile <profile>_<lang>)")
+	fs.StringVar(&args.Normalize, "normalize", "",
+		"Normalization steps (hyphens,nfc,quotes,dashes,space), all if empty, none to keep extracted text")
+	fs.StringVar(&args.FillFields, "fill_fields", "title,overtitle,lead",
+		"Fields checked against min_fill_rate")
+	fs.IntVar(&args.FillSample, "fill_sample", 20, "Downloaded articles before fill rates are checked")
+	fs.IntVar(&args.Retries, "retries", 3, "Retries of article download failed by network or status 5xx")
+	fs.DurationVar(&args.RetryBackoff, "retry_backoff", 2*time.Second, "Delay before first retry, doubled per retry")
+	profileFlags(fs, args)
+}
+
+// profileFlags registers auth, profile search and fill rate flags shared by crawl and doctor
+func profileFlags(fs *flag.FlagSet, args *cli.Arguments) {
+	fs.StringVar(&args.Login, "login", "", "Auth login")
+	fs.StringVar(&args.Password, "pass", "", "Auth password")
+	fs.StringVar(&args.ZeitMode, "zeit_mode", "1y", "Zeit mode")
+	fs.StringVar(&args.ZeitType, "zeit_type", "article", "Zeit type")
+	fs.IntVar(&args.SpiegelZeitraum, "spiegel_zeitraum", 365, "Spiegel zeitraum (in days)")
+	fs.StringVar(&args.SpiegelSuchbegriff, "spiegel_suchbegriff", "politik", "Spiegel suchbegriff")
+	fs.StringVar(&args.SpiegelInhalt, "spiegel_inhalt", "", "Spiegel inhalt (heading,title,intro)")
+	fs.StringVar(&args.SpiegelSegments, "spiegel_segments",
+		"spon,spon_paid,spon_international,mmo,mmo_paid,hbm,hbm_paid", "Spiegel segments")
+	fs.Float64Var(&args.MinFillRate, "min_fill_rate", 0.1,
+		"Minimal share of articles with non-empty field, crawl aborts below it (disabled if 0)")
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/sku4/mslu-parser/pkg/logger"
+	"os"
+)
+
+// loggerFlags registers logging flags shared by all commands
+func loggerFlags(fs *flag.FlagSet) *logger.Config {
+	cfg := &logger.Config{}
+	fs.StringVar(&cfg.Level, "log_level", "info", "Available: debug, info, warn, error")
+	fs.StringVar(&cfg.Format, "log_format", "json", "Available: json, console")
+	fs.StringVar(&cfg.File, "log_file", "", "Log file (stderr if empty)")
+	fs.IntVar(&cfg.MaxSize, "log_max_size", 100, "Log file size in megabytes before rotation")
+	fs.IntVar(&cfg.MaxBackups, "log_max_backups", 5, "Count of rotated log files to keep (all if 0)")
+	fs.IntVar(&cfg.MaxAge, "log_max_age", 30, "Days to keep rotated log files (forever if 0)")
+
+	return cfg
+}
+
+// initLogger configures the global logger and exits on invalid configuration
+func initLogger(cfg *logger.Config) {
+	if err := logger.Init(*cfg); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "error init logger: %s\n", err.Error())
+		os.Exit(2)
+	}
+}
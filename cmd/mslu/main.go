@@ -12,6 +12,7 @@ import (
 	"github.com/sku4/mslu-parser/internal/repository"
 	"github.com/sku4/mslu-parser/internal/service"
 	"github.com/sku4/mslu-parser/pkg/logger"
+	"github.com/sku4/mslu-parser/pkg/metrics"
 )
 
 func main() {
@@ -19,34 +20,75 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
+	// subcommands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "export":
+			export(ctx, os.Args[2:])
+			return
+		case "query":
+			query(ctx, os.Args[2:])
+			return
+		case "stats":
+			stats(ctx, os.Args[2:])
+			return
+		case "analyze":
+			analyze(ctx, os.Args[2:])
+			return
+		case "doctor":
+			doctor(ctx, os.Args[2:])
+			return
+		case "retry-failed":
+			retryFailed(ctx, os.Args[2:])
+			return
+		case "serve":
+			serve(ctx, os.Args[2:])
+			return
+		}
+	}
+
 	args := cli.Arguments{}
 	flag.IntVar(&args.Count, "count", 100, "Count download articles")
 	flag.StringVar(&args.Profile, "profile", "", "Available: zeit, spiegel")
-	flag.StringVar(&args.Login, "login", "", "Auth login")
-	flag.StringVar(&args.Password, "pass", "", "Auth password")
 	flag.BoolVar(&args.Update, "update", false, "Update downloaded articles")
-	flag.StringVar(&args.ZeitMode, "zeit_mode", "1y", "Zeit mode")
-	flag.StringVar(&args.ZeitType, "zeit_type", "article", "Zeit type")
-	flag.IntVar(&args.SpiegelZeitraum, "spiegel_zeitraum", 365, "Spiegel zeitraum (in days)")
-	flag.StringVar(&args.SpiegelSuchbegriff, "spiegel_suchbegriff", "politik", "Spiegel suchbegriff")
-	flag.StringVar(&args.SpiegelInhalt, "spiegel_inhalt", "", "Spiegel inhalt (heading,title,intro)")
-	flag.StringVar(&args.SpiegelSegments, "spiegel_segments",
-		"spon,spon_paid,spon_international,mmo,mmo_paid,hbm,hbm_paid", "Spiegel segments")
+	crawlFlags(flag.CommandLine, &args)
+	metricsAddr := flag.String("metrics", "", "Serve Prometheus metrics at /metrics on address, e.g. :9090")
+	repositoryConfig := repositoryFlags(flag.CommandLine)
+	loggerConfig := loggerFlags(flag.CommandLine)
 	flag.Parse()
+	initLogger(loggerConfig)
 	ctx = cli.SetArgs(ctx, args)
+	runID := cli.NewRunID()
+	ctx = cli.SetRunID(ctx, runID)
+	ctx = logger.WithContext(ctx, "run_id", runID)
 
-	repos := repository.NewRepository()
+	log := logger.FromContext(ctx)
+	repos, err := repository.NewRepository(*repositoryConfig)
+	if err != nil {
+		log.Fatalf("error init repository: %s", err.Error())
+	}
 	services := service.NewService(repos)
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
 
-	log := logger.Get()
-	log.Infof("App Started with args: '%s', count %d, update %t", args.Profile, args.Count, args.Update)
+	if *metricsAddr != "" {
+		go func() {
+			if err := metrics.Serve(ctx, *metricsAddr); err != nil {
+				log.Errorf("error metrics server: %s", err.Error())
+			}
+		}()
+	}
+
+	log.Infof("App Started run %s with args: '%s', count %d, update %t", runID, args.Profile, args.Count, args.Update)
 
+	// runErr holds error of the run, an aborted run such as collapsed fill rates exits non-zero
+	runErr := make(chan error, 1)
 	go func() {
-		if err := services.Parser.Run(ctx); err != nil {
-			log.Info(err.Error())
+		err := services.Parser.Run(ctx)
+		if err != nil {
+			log.Errorf("error run: %s", err.Error())
 		}
+		runErr <- err
 		quit <- nil
 	}()
 
@@ -55,12 +97,27 @@ func main() {
 	cancel()
 	log.Info("Context is stopped")
 
-	err := services.Parser.Shutdown()
+	err = services.Parser.Shutdown()
 	if err != nil {
 		log.Infof("error parser shutdown: %s", err.Error())
 	} else {
 		log.Info("Parser stopped")
 	}
 
+	report := services.Parser.Report()
+	report.Arguments = flagArguments(flag.CommandLine)
+	if reportPath, err := writeReport(repositoryConfig, report); err != nil {
+		log.Errorf("error write run report: %s", err.Error())
+	} else {
+		log.Infof("Run report saved to %s", reportPath)
+	}
+
 	log.Info("App Shutting Down")
+	select {
+	case err = <-runErr:
+		if err != nil {
+			os.Exit(1)
+		}
+	default:
+	}
 }
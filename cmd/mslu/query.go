@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"github.com/sku4/mslu-parser/internal/repository"
+	"github.com/sku4/mslu-parser/internal/service"
+	"github.com/sku4/mslu-parser/models/cli"
+	"github.com/sku4/mslu-parser/pkg/logger"
+	"strings"
+)
+
+func query(ctx context.Context, arguments []string) {
+	args := cli.QueryArguments{}
+	fs := flag.NewFlagSet("query", flag.ExitOnError)
+	fs.StringVar(&args.Mode, "mode", "word", "Available: word, phrase, regex")
+	fs.StringVar(&args.Fields, "fields", "", "Searched fields (title,overtitle,lead,subtitles,imagetitles)")
+	fs.IntVar(&args.Context, "context", 7, "Context words on each side")
+	fs.StringVar(&args.Sort, "sort", "", "Sort by neighbour: left, right")
+	fs.StringVar(&args.Format, "format", "text", "Available: text, csv, html")
+	fs.StringVar(&args.Output, "output", "", "Output file (stdout if empty)")
+	fs.StringVar(&args.Profile, "profile", "", "Filter by profile: zeit, spiegel")
+	fs.StringVar(&args.From, "from", "", "Filter by date from (YYYY-MM-DD)")
+	fs.StringVar(&args.To, "to", "", "Filter by date to inclusive (YYYY-MM-DD)")
+	repositoryConfig := repositoryFlags(fs)
+	loggerConfig := loggerFlags(fs)
+	_ = fs.Parse(arguments)
+	initLogger(loggerConfig)
+	args.Pattern = strings.Join(fs.Args(), " ")
+	ctx = cli.SetQueryArgs(ctx, args)
+
+	log := logger.Get()
+	repos, err := repository.NewRepository(*repositoryConfig)
+	if err != nil {
+		log.Errorf("error init repository: %s", err.Error())
+		return
+	}
+	services := service.NewService(repos)
+	defer func() {
+		if err := repos.Excel.Close(); err != nil {
+			log.Errorf("error repository close: %s", err.Error())
+		}
+	}()
+
+	w, err := createOutput(args.Output)
+	if err != nil {
+		log.Errorf("error create output: %s", err.Error())
+		return
+	}
+	defer func() {
+		_ = w.Close()
+	}()
+
+	if err = services.Querier.Query(ctx, w); err != nil {
+		log.Errorf("error query: %s", err.Error())
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"github.com/pkg/errors"
+	"github.com/sku4/mslu-parser/internal/repository"
+	"github.com/sku4/mslu-parser/models"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// secretFlags are parts of flag names redacted in run reports, credentials like "login" and "pass"
+// and any token or key flags added later
+var secretFlags = []string{"login", "pass", "token", "secret", "key", "auth"}
+
+// flagArguments returns values of all flags with secrets redacted
+func flagArguments(fs *flag.FlagSet) map[string]string {
+	arguments := make(map[string]string)
+	fs.VisitAll(func(f *flag.Flag) {
+		value := f.Value.String()
+		if value != "" && isSecretFlag(f.Name) {
+			value = "***"
+		}
+		arguments[f.Name] = value
+	})
+
+	return arguments
+}
+
+func isSecretFlag(name string) bool {
+	name = strings.ToLower(name)
+	for _, secret := range secretFlags {
+		if strings.Contains(name, secret) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writeReport saves report as json and text summary next to the repository file
+func writeReport(cfg *repository.Config, report *models.Report) (string, error) {
+	output := cfg.Xlsx
+	if cfg.Storage == "sqlite" {
+		output = cfg.Sqlite
+	}
+	name := filepath.Join(filepath.Dir(output), "report-"+report.RunID)
+
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", errors.Wrap(err, "marshal report")
+	}
+	if err = os.WriteFile(name+".json", append(b, '\n'), 0o644); err != nil {
+		return "", errors.Wrap(err, "write report")
+	}
+	if err = os.WriteFile(name+".txt", []byte(report.Summary()), 0o644); err != nil {
+		return "", errors.Wrap(err, "write report summary")
+	}
+
+	return name + ".json", nil
+}
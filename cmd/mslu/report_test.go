@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"github.com/sku4/mslu-parser/models/cli"
+	"testing"
+)
+
+func TestFlagArguments(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	args := cli.Arguments{}
+	crawlFlags(fs, &args)
+	repositoryFlags(fs)
+	fs.String("api_token", "", "Token of a later flag")
+	err := fs.Parse([]string{"-login", "user@example.com", "-pass", "secret", "-api_token", "abc", "-language", "drop"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	arguments := flagArguments(fs)
+	want := map[string]string{
+		"login":     "***",
+		"pass":      "***",
+		"api_token": "***",
+		"language":  "drop",
+		"xlsx":      fs.Lookup("xlsx").DefValue,
+	}
+	for name, value := range want {
+		if got := arguments[name]; got != value {
+			t.Errorf("flagArguments()[%q] = %q, want %q", name, got, value)
+		}
+	}
+
+	// unset secrets stay empty
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	crawlFlags(fs, &cli.Arguments{})
+	if got := flagArguments(fs)["pass"]; got != "" {
+		t.Errorf("flagArguments()[\"pass\"] = %q, want empty", got)
+	}
+}
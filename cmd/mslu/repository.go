@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"github.com/sku4/mslu-parser/internal/repository"
+	"io"
+	"os"
+)
+
+// repositoryFlags registers storage flags shared by all commands
+func repositoryFlags(fs *flag.FlagSet) *repository.Config {
+	cfg := &repository.Config{}
+	fs.StringVar(&cfg.Storage, "storage", "excel", "Available: excel, sqlite")
+	fs.StringVar(&cfg.Xlsx, "xlsx", "parser.xlsx", "Excel workbook path")
+	fs.IntVar(&cfg.Backups, "backups", 3, "Count of workbook versions before previous runs to keep")
+	fs.StringVar(&cfg.Sqlite, "sqlite", "parser.db", "SQLite database path")
+	fs.StringVar(&cfg.Failures, "failures", "failures",
+		"Directory for html, headers and status of articles failed extraction (not kept if empty)")
+	fs.StringVar(&cfg.DeadLetters, "dead_letters", "deadletters.json",
+		"File of failed urls with reason, attempts and next retry time (not kept if empty)")
+
+	return cfg
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// createOutput creates output file, empty path means stdout
+func createOutput(path string) (io.WriteCloser, error) {
+	if path == "" {
+		return nopCloser{os.Stdout}, nil
+	}
+
+	return os.Create(path)
+}
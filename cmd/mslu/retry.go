@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"github.com/sku4/mslu-parser/internal/repository"
+	"github.com/sku4/mslu-parser/internal/service"
+	"github.com/sku4/mslu-parser/models/cli"
+	"github.com/sku4/mslu-parser/pkg/logger"
+	"os"
+)
+
+// retryFailed downloads again articles kept in the failures directory or dead letters,
+// e.g. after selectors are fixed
+func retryFailed(ctx context.Context, arguments []string) {
+	fs := flag.NewFlagSet("retry-failed", flag.ExitOnError)
+	args := cli.Arguments{}
+	fs.StringVar(&args.Profile, "profile", "", "Available: zeit, spiegel")
+	crawlFlags(fs, &args)
+	repositoryConfig := repositoryFlags(fs)
+	loggerConfig := loggerFlags(fs)
+	_ = fs.Parse(arguments)
+	initLogger(loggerConfig)
+	ctx = cli.SetArgs(ctx, args)
+	runID := cli.NewRunID()
+	ctx = cli.SetRunID(ctx, runID)
+	ctx = logger.WithContext(ctx, "run_id", runID)
+
+	log := logger.FromContext(ctx)
+	repos, err := repository.NewRepository(*repositoryConfig)
+	if err != nil {
+		log.Fatalf("error init repository: %s", err.Error())
+	}
+	services := service.NewService(repos)
+
+	// failed retry exits non-zero after the report is written
+	log.Infof("Retry failed articles of '%s' run %s", args.Profile, runID)
+	retryErr := services.Parser.RetryFailed(ctx)
+	if retryErr != nil {
+		log.Errorf("error retry failed: %s", retryErr.Error())
+	}
+	if err = services.Parser.Shutdown(); err != nil {
+		log.Errorf("error parser shutdown: %s", err.Error())
+	}
+
+	report := services.Parser.Report()
+	report.Arguments = flagArguments(fs)
+	if reportPath, err := writeReport(repositoryConfig, report); err != nil {
+		log.Errorf("error write run report: %s", err.Error())
+	} else {
+		log.Infof("Run report saved to %s", reportPath)
+	}
+	if retryErr != nil {
+		os.Exit(1)
+	}
+}
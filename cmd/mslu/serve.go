@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"github.com/sku4/mslu-parser/internal/handler"
+	"github.com/sku4/mslu-parser/internal/repository"
+	"github.com/sku4/mslu-parser/internal/service"
+	"github.com/sku4/mslu-parser/models/cli"
+	"github.com/sku4/mslu-parser/pkg/logger"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// serve runs http api for crawl jobs, crawl flags are defaults of job arguments
+func serve(ctx context.Context, arguments []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:8080", "Listen address, the api has no authentication")
+	hosts := fs.String("hosts", "", "Host names of requests allowed besides localhost and host of addr, comma separated")
+	defaults := cli.Arguments{}
+	fs.IntVar(&defaults.Count, "count", 100, "Count download articles")
+	fs.StringVar(&defaults.Profile, "profile", "", "Available: zeit, spiegel")
+	fs.BoolVar(&defaults.Update, "update", false, "Update downloaded articles")
+	crawlFlags(fs, &defaults)
+	repositoryConfig := repositoryFlags(fs)
+	loggerConfig := loggerFlags(fs)
+	_ = fs.Parse(arguments)
+	initLogger(loggerConfig)
+
+	log := logger.Get()
+	repos, err := repository.NewRepository(*repositoryConfig)
+	if err != nil {
+		log.Errorf("error init repository: %s", err.Error())
+		return
+	}
+	defer func() {
+		if err := repos.Excel.Close(); err != nil {
+			log.Errorf("error repository close: %s", err.Error())
+		}
+	}()
+	services := service.NewService(repository.NewShared(repos))
+	processed := make(chan struct{})
+	go func() {
+		services.Jobs.Process(ctx)
+		close(processed)
+	}()
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           handler.NewHandler(services, defaults, *addr, strings.Split(*hosts, ",")).Routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.Infof("Serve api at %s", *addr)
+	if err = server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Errorf("error serve: %s", err.Error())
+	}
+	// running job stops with context and saves downloaded articles before repository is closed
+	<-processed
+	log.Info("Server stopped")
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/sku4/mslu-parser/internal/repository"
+	"github.com/sku4/mslu-parser/internal/service"
+	"github.com/sku4/mslu-parser/models/cli"
+	"github.com/sku4/mslu-parser/pkg/logger"
+	"os"
+)
+
+func stats(ctx context.Context, arguments []string) {
+	if len(arguments) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "Usage: mslu stats freq|colloc|headlines|readability|lexicon|entities [flags]")
+		os.Exit(2)
+	}
+	command := arguments[0]
+	by := "outlet,field,month"
+
+	args := cli.StatsArguments{}
+	fs := flag.NewFlagSet("stats "+command, flag.ExitOnError)
+	fs.StringVar(&args.Format, "format", "csv", "Available: csv, xlsx")
+	fs.StringVar(&args.Output, "output", "", "Output file (stdout if empty)")
+	fs.StringVar(&args.Fields, "fields", "", "Analyzed fields (title,overtitle,lead,subtitles,imagetitles)")
+	fs.StringVar(&args.Profile, "profile", "", "Filter by profile: zeit, spiegel")
+	fs.StringVar(&args.From, "from", "", "Filter by date from (YYYY-MM-DD)")
+	fs.StringVar(&args.To, "to", "", "Filter by date to inclusive (YYYY-MM-DD)")
+	fs.BoolVar(&args.Unique, "unique", false, "Exclude near-duplicates of earlier articles")
+	fs.BoolVar(&args.Lower, "lower", true, "Lowercase word forms")
+	fs.IntVar(&args.Top, "top", 0, "Keep top items per group (all if 0)")
+	switch command {
+	case "freq":
+		fs.IntVar(&args.N, "n", 0, "N-gram size 1-3 (all if 0)")
+		fs.IntVar(&args.Min, "min", 1, "Minimum count")
+	case "colloc":
+		fs.StringVar(&args.Node, "node", "", "Node word")
+		fs.IntVar(&args.Window, "window", 5, "Window size on each side of node")
+		fs.StringVar(&args.Measure, "measure", "logdice", "Sort by: ll, mi, t, logdice")
+		fs.IntVar(&args.Min, "min", 2, "Minimum co-occurrence count")
+		by = "outlet"
+	case "headlines":
+		by = "outlet"
+	case "readability":
+		by = "outlet,month"
+	case "lexicon":
+		by = "outlet"
+	case "entities":
+		fs.StringVar(&args.Types, "types", "", "Entity types (person,place,organization,party), all if empty")
+		fs.IntVar(&args.Min, "min", 1, "Minimum mentions")
+		by = "outlet,field"
+	}
+	fs.StringVar(&args.By, "by", by, "Split by: outlet, field, month")
+	repositoryConfig := repositoryFlags(fs)
+	loggerConfig := loggerFlags(fs)
+	_ = fs.Parse(arguments[1:])
+	initLogger(loggerConfig)
+	log := logger.Get()
+	ctx = cli.SetStatsArgs(ctx, args)
+
+	repos, err := repository.NewRepository(*repositoryConfig)
+	if err != nil {
+		log.Errorf("error init repository: %s", err.Error())
+		return
+	}
+	services := service.NewService(repos)
+	defer func() {
+		if err := repos.Excel.Close(); err != nil {
+			log.Errorf("error repository close: %s", err.Error())
+		}
+	}()
+
+	w, err := createOutput(args.Output)
+	if err != nil {
+		log.Errorf("error create output: %s", err.Error())
+		return
+	}
+	defer func() {
+		_ = w.Close()
+	}()
+
+	switch command {
+	case "freq":
+		err = services.Stats.Freq(ctx, w)
+	case "colloc":
+		err = services.Stats.Colloc(ctx, w)
+	case "headlines":
+		err = services.Stats.Headlines(ctx, w)
+	case "readability":
+		err = services.Stats.Readability(ctx, w)
+	case "lexicon":
+		err = services.Stats.Lexicon(ctx, w)
+	case "entities":
+		err = services.Stats.Entities(ctx, w)
+	default:
+		err = fmt.Errorf("stats command '%s' not found", command)
+	}
+	if err != nil {
+		log.Errorf("error stats %s: %s", command, err.Error())
+	}
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/sku4/mslu-parser/models"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// articles lists articles, query parameters: outlet, from, to, contains, fields, has_captions,
+// unique, page and per_page
+func (h *Handler) articles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	query, err := articleQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	page, err := h.services.Corpus.Articles(r.Context(), query)
+	if errors.Is(err, models.PageOutOfRangeError) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// article returns article of /api/articles/{id} with all versions
+func (h *Handler) article(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	parts := pathParts(r.URL.Path, "/api/articles/")
+	if len(parts) != 1 {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+
+	article, err := h.services.Corpus.Article(r.Context(), parts[0])
+	if errors.Is(err, models.ArticleNotFoundError) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, article)
+}
+
+// counts returns article counts, query parameters: by (outlet,month), outlet, from, to and unique
+func (h *Handler) counts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	values := r.URL.Query()
+	filter, err := queryFilter(values)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	by := values.Get("by")
+	if by == "" {
+		by = "outlet,month"
+	}
+
+	counts, err := h.services.Corpus.Counts(r.Context(), by, filter)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, counts)
+}
+
+func articleQuery(values url.Values) (query models.ArticleQuery, err error) {
+	if query.Filter, err = queryFilter(values); err != nil {
+		return query, err
+	}
+	query.Contains = values.Get("contains")
+	query.Fields = models.TextFields
+	if values.Get("fields") != "" {
+		if query.Fields, err = models.ParseFields(values.Get("fields")); err != nil {
+			return query, err
+		}
+	}
+	if v := values.Get("has_captions"); v != "" {
+		hasCaptions, err := strconv.ParseBool(v)
+		if err != nil {
+			return query, errors.New(fmt.Sprintf("invalid has_captions '%s'", v))
+		}
+		query.HasCaptions = &hasCaptions
+	}
+	if query.Page, err = intParam(values, "page", 1); err != nil {
+		return query, err
+	}
+	if query.PerPage, err = intParam(values, "per_page", 0); err != nil {
+		return query, err
+	}
+
+	return query, nil
+}
+
+// queryFilter parses outlet, from, to (YYYY-MM-DD) and unique parameters
+func queryFilter(values url.Values) (models.Filter, error) {
+	filter, err := models.NewFilter(values.Get("outlet"), values.Get("from"), values.Get("to"))
+	if err != nil {
+		return filter, err
+	}
+	if v := values.Get("unique"); v != "" {
+		if filter.Unique, err = strconv.ParseBool(v); err != nil {
+			return filter, errors.New(fmt.Sprintf("invalid unique '%s'", v))
+		}
+	}
+
+	return filter, nil
+}
+
+func intParam(values url.Values, name string, def int) (int, error) {
+	v := values.Get(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, errors.New(fmt.Sprintf("invalid %s '%s'", name, v))
+	}
+
+	return n, nil
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/sku4/mslu-parser/internal/service"
+	"github.com/sku4/mslu-parser/models/cli"
+	"github.com/sku4/mslu-parser/pkg/logger"
+	"github.com/sku4/mslu-parser/pkg/metrics"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type Handler struct {
+	services *service.Service
+	defaults cli.Arguments
+	hosts    map[string]bool
+}
+
+// NewHandler returns handler of services, defaults are arguments of jobs not set in the request,
+// requests are served for loopback names, the host of addr and hosts only
+func NewHandler(services *service.Service, defaults cli.Arguments, addr string, hosts []string) *Handler {
+	h := &Handler{
+		services: services,
+		defaults: defaults,
+		hosts: map[string]bool{
+			"localhost": true,
+			"127.0.0.1": true,
+			"::1":       true,
+		},
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil && host != "" {
+		if ip := net.ParseIP(host); ip == nil || !ip.IsUnspecified() {
+			h.hosts[hostName(host)] = true
+		}
+	}
+	for _, host := range hosts {
+		if host = hostName(host); host != "" {
+			h.hosts[host] = true
+		}
+	}
+
+	return h
+}
+
+// hostName returns lowercase host of Host header value without port
+func hostName(host string) string {
+	if name, _, err := net.SplitHostPort(host); err == nil {
+		host = name
+	}
+
+	return strings.TrimSuffix(strings.ToLower(strings.Trim(host, "[]")), ".")
+}
+
+// checkHost rejects requests for other host names, a page of another site rebinding its name
+// to the listen address passes the origin check but not this one
+func (h *Handler) checkHost(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !h.hosts[hostName(r.Host)] {
+			writeError(w, http.StatusMisdirectedRequest, errors.New("host not allowed"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *Handler) Routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/jobs", h.jobs)
+	mux.HandleFunc("/api/jobs/", h.job)
+	mux.HandleFunc("/api/articles", h.articles)
+	mux.HandleFunc("/api/articles/", h.article)
+	mux.HandleFunc("/api/counts", h.counts)
+	mux.Handle("/metrics", metrics.Handler())
+	mux.HandleFunc("/", h.index)
+	mux.HandleFunc("/articles/", h.webArticle)
+	mux.HandleFunc("/jobs", h.webJobs)
+	mux.HandleFunc("/jobs/", h.webJob)
+
+	return h.checkHost(mux)
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		logger.Get().Errorf("error write response: %s", err.Error())
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func methodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+}
+
+// sameOrigin rejects form posts of other sites, browsers send Origin with every post
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+
+	return err == nil && u.Host == r.Host
+}
+
+// checkMutation rejects requests changing jobs from other sites and json bodies of other content types,
+// browsers post text/plain forms across origins without preflight
+func checkMutation(w http.ResponseWriter, r *http.Request, body bool) bool {
+	if !sameOrigin(r) {
+		writeError(w, http.StatusForbidden, errors.New("cross origin request"))
+		return false
+	}
+	if body {
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || mediaType != "application/json" {
+			writeError(w, http.StatusUnsupportedMediaType, errors.New("content type must be application/json"))
+			return false
+		}
+	}
+
+	return true
+}
+
+// pathParts splits path after prefix, "/api/jobs/1/export" with prefix "/api/jobs/" is ["1", "export"]
+func pathParts(path, prefix string) []string {
+	return strings.Split(strings.Trim(strings.TrimPrefix(path, prefix), "/"), "/")
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"github.com/sku4/mslu-parser/models/cli"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckHost(t *testing.T) {
+	tests := []struct {
+		name  string
+		addr  string
+		hosts []string
+		host  string
+		want  int
+	}{
+		{"localhost", "127.0.0.1:8080", nil, "localhost:8080", http.StatusOK},
+		{"loopback ip", "127.0.0.1:8080", nil, "127.0.0.1:8080", http.StatusOK},
+		{"loopback ipv6", "127.0.0.1:8080", nil, "[::1]:8080", http.StatusOK},
+		{"listen host", "10.0.0.5:8080", nil, "10.0.0.5:8080", http.StatusOK},
+		{"allowed host", "0.0.0.0:8080", []string{"mslu.example.org"}, "MSLU.example.org.:8080", http.StatusOK},
+		{"rebound name", "127.0.0.1:8080", nil, "attacker.example.com:8080", http.StatusMisdirectedRequest},
+		{"unspecified listen host", "0.0.0.0:8080", nil, "0.0.0.0:8080", http.StatusMisdirectedRequest},
+		{"empty hosts flag", "127.0.0.1:8080", []string{""}, "attacker.example.com", http.StatusMisdirectedRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(nil, cli.Arguments{}, tt.addr, tt.hosts)
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			r := httptest.NewRequest(http.MethodGet, "/api/counts", nil)
+			r.Host = tt.host
+			w := httptest.NewRecorder()
+			h.checkHost(next).ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
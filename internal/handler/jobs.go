@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/sku4/mslu-parser/internal/service/export"
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/models/cli"
+	"net/http"
+	"strconv"
+)
+
+// maxJobRequest limits size of job arguments
+const maxJobRequest = 64 << 10
+
+// jobs lists jobs on GET and creates job from json arguments on POST
+func (h *Handler) jobs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, h.services.Jobs.List())
+	case http.MethodPost:
+		if !checkMutation(w, r, true) {
+			return
+		}
+		args := h.defaults
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxJobRequest))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&args); err != nil {
+			writeError(w, http.StatusBadRequest, errors.Wrap(err, "decode job arguments"))
+			return
+		}
+		if args.Profile == "" {
+			writeError(w, http.StatusBadRequest, errors.New("profile not set"))
+			return
+		}
+		job, err := h.services.Jobs.Create(args)
+		if err != nil {
+			writeError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		w.Header().Set("Location", "/api/jobs/"+job.ID)
+		writeJSON(w, http.StatusCreated, job)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+// job routes /api/jobs/{id}, /api/jobs/{id}/cancel and /api/jobs/{id}/export
+func (h *Handler) job(w http.ResponseWriter, r *http.Request) {
+	parts := pathParts(r.URL.Path, "/api/jobs/")
+	id := parts[0]
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		job, err := h.services.Jobs.Get(id)
+		jobResponse(w, job, err)
+	case len(parts) == 1 && r.Method == http.MethodDelete,
+		len(parts) == 2 && parts[1] == "cancel" && r.Method == http.MethodPost:
+		if !checkMutation(w, r, false) {
+			return
+		}
+		job, err := h.services.Jobs.Cancel(id)
+		jobResponse(w, job, err)
+	case len(parts) == 2 && parts[1] == "export" && r.Method == http.MethodGet:
+		h.jobExport(w, r, id)
+	case len(parts) <= 2:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost, http.MethodDelete)
+	default:
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+func jobResponse(w http.ResponseWriter, job models.Job, err error) {
+	if errors.Is(err, models.JobNotFoundError) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// jobExport sends articles saved by job, query parameters are those of export command
+func (h *Handler) jobExport(w http.ResponseWriter, r *http.Request, id string) {
+	query := r.URL.Query()
+	args := cli.ExportArguments{
+		Format:      query.Get("format"),
+		Fields:      query.Get("fields"),
+		Joiner:      query.Get("joiner"),
+		Match:       query.Get("match"),
+		MatchFields: query.Get("match_fields"),
+	}
+	if args.Format == "" {
+		args.Format = "jsonl"
+	}
+	if args.Joiner == "" {
+		args.Joiner = "|"
+	}
+	args.Unique, _ = strconv.ParseBool(query.Get("unique"))
+
+	job, err := h.services.Jobs.Get(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if job.Status == models.JobPending || job.Status == models.JobRunning {
+		writeError(w, http.StatusConflict, errors.New(fmt.Sprintf("job is %s", job.Status)))
+		return
+	}
+
+	// export is buffered to answer errors with status
+	var b bytes.Buffer
+	if err = h.services.Jobs.Export(cli.SetExportArgs(r.Context(), args), id, &b); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="job-%s.%s"`, id, export.Extension(args.Format)))
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, _ = w.Write(b.Bytes())
+}
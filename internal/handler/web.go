@@ -0,0 +1,345 @@
+package handler
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/models/cli"
+	"github.com/sku4/mslu-parser/pkg/logger"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+//go:embed templates/*.html
+var templatesFS embed.FS
+
+// webPerPage is the count of articles on a page of the web ui
+const webPerPage = 50
+
+// profiles are offered in the crawl form of the web ui
+var profiles = []string{"zeit", "spiegel"}
+
+var webFuncs = template.FuncMap{
+	"date":    formatDate,
+	"excerpt": excerpt,
+	"total": func(counts map[string]int) int {
+		sum := 0
+		for _, count := range counts {
+			sum += count
+		}
+		return sum
+	},
+}
+
+// pages are parsed with the layout each, every page defines its own content template
+var pages = parsePages("articles.html", "article.html", "jobs.html")
+
+func parsePages(names ...string) map[string]*template.Template {
+	pages := make(map[string]*template.Template, len(names))
+	for _, name := range names {
+		pages[name] = template.Must(template.New(name).Funcs(webFuncs).
+			ParseFS(templatesFS, "templates/layout.html", "templates/"+name))
+	}
+
+	return pages
+}
+
+type webPage struct {
+	Title   string
+	Refresh bool
+	Error   string
+}
+
+type articlesPage struct {
+	webPage
+	Contains string
+	Outlet   string
+	From     string
+	To       string
+	Captions string
+	Page     models.ArticlePage
+	PrevURL  string
+	NextURL  string
+}
+
+type articlePage struct {
+	webPage
+	Article models.Article
+	History []headline
+}
+
+// headline is a row of headline history, changed flags compare it with the previous row
+type headline struct {
+	Replaced         time.Time
+	Current          bool
+	OverTitle        string
+	Title            string
+	Lead             string
+	OverTitleChanged bool
+	TitleChanged     bool
+	LeadChanged      bool
+}
+
+type jobsPage struct {
+	webPage
+	Profiles []string
+	Defaults cli.Arguments
+	Jobs     []models.Job
+}
+
+// index lists and searches articles, query parameters are those of the articles api
+func (h *Handler) index(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	values := r.URL.Query()
+	data := articlesPage{
+		webPage:  webPage{Title: "Articles"},
+		Contains: values.Get("contains"),
+		Outlet:   values.Get("outlet"),
+		From:     values.Get("from"),
+		To:       values.Get("to"),
+		Captions: values.Get("has_captions"),
+	}
+	query, err := articleQuery(values)
+	if err != nil {
+		data.Error = err.Error()
+		render(w, http.StatusBadRequest, "articles.html", data)
+		return
+	}
+	query.Fields = []models.Field{models.FieldTitle, models.FieldLead}
+	query.PerPage = webPerPage
+	if data.Page, err = h.services.Corpus.Articles(r.Context(), query); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, models.PageOutOfRangeError) {
+			status = http.StatusBadRequest
+		}
+		data.Error = err.Error()
+		render(w, status, "articles.html", data)
+		return
+	}
+
+	if data.Page.Page > 1 {
+		data.PrevURL = pageURL(values, data.Page.Page-1)
+	}
+	if data.Page.Page*data.Page.PerPage < data.Page.Total {
+		data.NextURL = pageURL(values, data.Page.Page+1)
+	}
+	render(w, http.StatusOK, "articles.html", data)
+}
+
+// webArticle shows article of /articles/{id} with its headline history
+func (h *Handler) webArticle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	parts := pathParts(r.URL.Path, "/articles/")
+	if len(parts) != 1 {
+		http.NotFound(w, r)
+		return
+	}
+
+	article, err := h.services.Corpus.Article(r.Context(), parts[0])
+	if errors.Is(err, models.ArticleNotFoundError) {
+		http.NotFound(w, r)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	render(w, http.StatusOK, "article.html", articlePage{
+		webPage: webPage{Title: article.Title},
+		Article: article,
+		History: history(article),
+	})
+}
+
+// webJobs lists crawls on GET and starts crawl of form profile, count and update on POST
+func (h *Handler) webJobs(w http.ResponseWriter, r *http.Request) {
+	data := jobsPage{
+		webPage:  webPage{Title: "Crawls"},
+		Profiles: profiles,
+		Defaults: h.defaults,
+	}
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if !sameOrigin(r) {
+			http.Error(w, "cross origin request", http.StatusForbidden)
+			return
+		}
+		args, err := h.formArguments(w, r)
+		if err == nil {
+			_, err = h.services.Jobs.Create(args)
+		}
+		if err == nil {
+			http.Redirect(w, r, "/jobs", http.StatusSeeOther)
+			return
+		}
+		data.Error = err.Error()
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+		return
+	}
+
+	data.Jobs = h.services.Jobs.List()
+	// newest crawls first
+	for i, j := 0, len(data.Jobs)-1; i < j; i, j = i+1, j-1 {
+		data.Jobs[i], data.Jobs[j] = data.Jobs[j], data.Jobs[i]
+	}
+	for _, job := range data.Jobs {
+		data.Refresh = data.Refresh || job.Status == models.JobPending || job.Status == models.JobRunning
+	}
+	status := http.StatusOK
+	if data.Error != "" {
+		status = http.StatusBadRequest
+	}
+	render(w, status, "jobs.html", data)
+}
+
+// webJob stops crawl of /jobs/{id}/cancel
+func (h *Handler) webJob(w http.ResponseWriter, r *http.Request) {
+	parts := pathParts(r.URL.Path, "/jobs/")
+	if len(parts) != 2 || parts[1] != "cancel" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	if !sameOrigin(r) {
+		http.Error(w, "cross origin request", http.StatusForbidden)
+		return
+	}
+	if _, err := h.services.Jobs.Cancel(parts[0]); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Redirect(w, r, "/jobs", http.StatusSeeOther)
+}
+
+// formArguments returns handler defaults with profile, count and update of crawl form
+func (h *Handler) formArguments(w http.ResponseWriter, r *http.Request) (cli.Arguments, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxJobRequest)
+	if err := r.ParseForm(); err != nil {
+		return cli.Arguments{}, errors.Wrap(err, "parse form")
+	}
+
+	args := h.defaults
+	args.Profile = r.PostForm.Get("profile")
+	known := false
+	for _, profile := range profiles {
+		known = known || profile == args.Profile
+	}
+	if !known {
+		return args, errors.New(fmt.Sprintf("unknown profile '%s'", args.Profile))
+	}
+	if v := r.PostForm.Get("count"); v != "" {
+		count, err := strconv.Atoi(v)
+		if err != nil || count < 1 {
+			return args, errors.New(fmt.Sprintf("invalid count '%s'", v))
+		}
+		args.Count = count
+	}
+	args.Update = r.PostForm.Get("update") == "true"
+
+	return args, nil
+}
+
+// history returns headline history of article from the oldest version to the current text,
+// empty when article was never changed
+func history(article models.Article) []headline {
+	if len(article.Versions) == 0 {
+		return nil
+	}
+
+	rows := make([]headline, 0, len(article.Versions)+1)
+	for _, v := range article.Versions {
+		rows = append(rows, headline{
+			Replaced:  v.Replaced,
+			OverTitle: v.OverTitle,
+			Title:     v.Title,
+			Lead:      v.Lead,
+		})
+	}
+	rows = append(rows, headline{
+		Current:   true,
+		OverTitle: article.OverTitle,
+		Title:     article.Title,
+		Lead:      article.Lead,
+	})
+	for i := 1; i < len(rows); i++ {
+		rows[i].OverTitleChanged = rows[i].OverTitle != rows[i-1].OverTitle
+		rows[i].TitleChanged = rows[i].Title != rows[i-1].Title
+		rows[i].LeadChanged = rows[i].Lead != rows[i-1].Lead
+	}
+
+	return rows
+}
+
+// render writes page executed with layout, execution is buffered to answer errors with status
+func render(w http.ResponseWriter, status int, name string, data interface{}) {
+	var b bytes.Buffer
+	if err := pages[name].ExecuteTemplate(&b, "layout", data); err != nil {
+		logger.Get().Errorf("error render %s: %s", name, err.Error())
+		http.Error(w, "error render page", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = w.Write(b.Bytes())
+}
+
+func pageURL(values url.Values, page int) string {
+	query := url.Values{}
+	for key, value := range values {
+		query[key] = value
+	}
+	query.Set("page", strconv.Itoa(page))
+
+	return "/?" + query.Encode()
+}
+
+func formatDate(v interface{}) string {
+	switch t := v.(type) {
+	case time.Time:
+		if !t.IsZero() {
+			return t.Format("2006-01-02 15:04")
+		}
+	case *time.Time:
+		if t != nil && !t.IsZero() {
+			return t.Format("2006-01-02 15:04")
+		}
+	}
+
+	return ""
+}
+
+// excerpt shortens text to max runes at a word boundary
+func excerpt(text string, max int) string {
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	cut := string([]rune(text)[:max])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+
+	return cut + "…"
+}
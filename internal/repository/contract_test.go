@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/sku4/mslu-parser/models"
+	"hash/crc32"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// storages opens every storage backend in a new directory, all of them follow the same contract
+var storages = []struct {
+	name   string
+	config func(dir string) Config
+}{
+	{"excel", func(dir string) Config {
+		return Config{Storage: "excel", Xlsx: filepath.Join(dir, "parser.xlsx")}
+	}},
+	{"sqlite", func(dir string) Config {
+		return Config{Storage: "sqlite", Sqlite: filepath.Join(dir, "parser.db")}
+	}},
+}
+
+var contractComplexes = []models.Complex{
+	{
+		ExcelUrl:    models.ExcelUrl{Url: "https://www.zeit.de/politik/a"},
+		Profile:     "zeit",
+		Date:        time.Date(2023, 3, 14, 10, 0, 0, 0, time.UTC),
+		Author:      "Jörg Müller",
+		Title:       "Scholz verteidigt die Zeitenwende",
+		OverTitle:   "Bundestag",
+		Lead:        "ÄRGER über den \"Haushalt\" der Regierung.",
+		Subtitles:   []string{"Kritik aus der Union", "Was kommt jetzt?"},
+		ImageTitles: []string{"Olaf Scholz in Berlin"},
+		Analysis:    models.Analysis{Headline: &models.Headline{Words: 4}},
+		Raw:         &models.Raw{Title: "Scholz verteidigt die  Zeitenwende"},
+	},
+	{
+		ExcelUrl:    models.ExcelUrl{Url: "https://www.spiegel.de/politik/b"},
+		Profile:     "spiegel",
+		Title:       "Merkel trifft Macron",
+		Lead:        "Die Kanzlerin ab Montag in Paris.",
+		Subtitles:   []string{},
+		ImageTitles: []string{},
+	},
+}
+
+func TestStorageContract(t *testing.T) {
+	ctx := context.Background()
+	for _, storage := range storages {
+		t.Run(storage.name, func(t *testing.T) {
+			cfg := storage.config(t.TempDir())
+			repos, err := NewRepository(cfg)
+			if err != nil {
+				t.Fatalf("NewRepository() error = %v", err)
+			}
+			if err = repos.SetComplexes(ctx, contractComplexes); err != nil {
+				t.Fatalf("SetComplexes() error = %v", err)
+			}
+
+			// stored complexes read back equal
+			checkComplexes(t, repos, contractComplexes)
+			got, err := repos.GetComplex(ctx, models.ArticleID(contractComplexes[0].Url))
+			if err != nil || got.Title != contractComplexes[0].Title {
+				t.Errorf("GetComplex() = %q, %v, want %q", got.Title, err, contractComplexes[0].Title)
+			}
+			if _, err = repos.GetComplex(ctx, "unknown"); !errors.Is(err, models.ArticleNotFoundError) {
+				t.Errorf("GetComplex(unknown) error = %v, want %v", err, models.ArticleNotFoundError)
+			}
+
+			// updates keep the replaced text as version
+			used, err := repos.GetUsedUrls(ctx)
+			if err != nil {
+				t.Fatalf("GetUsedUrls() error = %v", err)
+			}
+			updated := contractComplexes[0]
+			updated.ExcelRow = used[crc32.ChecksumIEEE([]byte(updated.Url))]
+			if updated.ExcelRow == nil {
+				t.Fatalf("GetUsedUrls() misses %s", updated.Url)
+			}
+			updated.Title = "Scholz verteidigt die Zeitenwende erneut"
+			if err = repos.SetComplex(ctx, updated); err != nil {
+				t.Fatalf("SetComplex() error = %v", err)
+			}
+			want := []models.Complex{updated, contractComplexes[1]}
+			checkComplexes(t, repos, want)
+			versions, err := repos.GetVersions(ctx, updated.Url)
+			if err != nil || len(versions) != 1 || versions[0].Title != contractComplexes[0].Title {
+				t.Errorf("GetVersions() = %+v, %v, want one version of title %q", versions, err, contractComplexes[0].Title)
+			}
+
+			// stored complexes survive reopening
+			if err = repos.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if repos, err = NewRepository(cfg); err != nil {
+				t.Fatalf("NewRepository() error = %v", err)
+			}
+			defer func() {
+				_ = repos.Close()
+			}()
+			checkComplexes(t, repos, want)
+		})
+	}
+}
+
+func TestStorageSearch(t *testing.T) {
+	zeit, spiegel := contractComplexes[0].Url, contractComplexes[1].Url
+	text := []models.Field{models.FieldTitle, models.FieldLead, models.FieldSubtitles}
+	tests := []struct {
+		name   string
+		phrase string
+		fields []models.Field
+		want   []string
+	}{
+		{"word", "Zeitenwende", text, []string{zeit}},
+		{"inside word", "wende", text, []string{zeit}},
+		{"case-insensitive", "zeitenWENDE", text, []string{zeit}},
+		{"case-insensitive umlaut", "ärger über", text, []string{zeit}},
+		{"short phrase", "ab", text, []string{spiegel}},
+		{"several articles", "die", text, []string{zeit, spiegel}},
+		{"quote", `"haushalt"`, text, []string{zeit}},
+		{"list value", "kommt jetzt", text, []string{zeit}},
+		{"across list values", "Union Was", text, nil},
+		{"other field", "Zeitenwende", []models.Field{models.FieldLead}, nil},
+		{"non-text field", "zeit", []models.Field{models.FieldUrl, models.FieldProfile}, nil},
+		{"not found", "Hamburg", text, nil},
+	}
+
+	ctx := context.Background()
+	for _, storage := range storages {
+		t.Run(storage.name, func(t *testing.T) {
+			repos, err := NewRepository(storage.config(t.TempDir()))
+			if err != nil {
+				t.Fatalf("NewRepository() error = %v", err)
+			}
+			defer func() {
+				_ = repos.Close()
+			}()
+			if err = repos.SetComplexes(ctx, contractComplexes); err != nil {
+				t.Fatalf("SetComplexes() error = %v", err)
+			}
+
+			for _, tt := range tests {
+				found, err := repos.Search(ctx, tt.phrase, tt.fields)
+				if err != nil {
+					t.Fatalf("Search(%q) error = %v", tt.phrase, err)
+				}
+				var got []string
+				for _, cx := range found {
+					got = append(got, cx.Url)
+				}
+				sort.Strings(got)
+				want := append([]string(nil), tt.want...)
+				sort.Strings(want)
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("%s: Search(%q) = %q, want %q", tt.name, tt.phrase, got, want)
+				}
+			}
+		})
+	}
+}
+
+// checkComplexes compares stored complexes by url ignoring storage rows
+func checkComplexes(t *testing.T, repos *Repository, want []models.Complex) {
+	t.Helper()
+	complexes, err := repos.GetComplexes(context.Background())
+	if err != nil {
+		t.Fatalf("GetComplexes() error = %v", err)
+	}
+	if len(complexes) != len(want) {
+		t.Fatalf("GetComplexes() = %d complexes, want %d", len(complexes), len(want))
+	}
+	byUrl := make(map[string]models.Complex, len(complexes))
+	for _, cx := range complexes {
+		cx.ExcelRow = nil
+		byUrl[cx.Url] = cx
+	}
+	for _, cx := range want {
+		cx.ExcelRow = nil
+		got, ok := byUrl[cx.Url]
+		if !ok {
+			t.Errorf("GetComplexes() misses %s", cx.Url)
+			continue
+		}
+		if !got.Date.Equal(cx.Date) {
+			t.Errorf("GetComplexes() %s date = %v, want %v", cx.Url, got.Date, cx.Date)
+		}
+		got.Date, cx.Date = time.Time{}, time.Time{}
+		if !reflect.DeepEqual(got, cx) {
+			t.Errorf("GetComplexes() %s = %+v, want %+v", cx.Url, got, cx)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/sku4/mslu-parser/models"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// DeadLetter keeps failed urls in a json file, the file is read on first use and
+// rewritten on every change
+type DeadLetter struct {
+	path    string
+	mutex   sync.Mutex
+	letters map[string]models.DeadLetter
+}
+
+func New(path string) *DeadLetter {
+	return &DeadLetter{
+		path: path,
+	}
+}
+
+func (d *DeadLetter) Get(_ context.Context, url string) (*models.DeadLetter, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if err := d.load(); err != nil {
+		return nil, err
+	}
+	letter, ok := d.letters[url]
+	if !ok {
+		return nil, nil
+	}
+
+	return &letter, nil
+}
+
+func (d *DeadLetter) Put(_ context.Context, letter models.DeadLetter) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if err := d.load(); err != nil {
+		return err
+	}
+	d.letters[letter.Url] = letter
+
+	return d.save()
+}
+
+// Remove deletes url, missing url is not an error
+func (d *DeadLetter) Remove(_ context.Context, url string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if err := d.load(); err != nil {
+		return err
+	}
+	if _, ok := d.letters[url]; !ok {
+		return nil
+	}
+	delete(d.letters, url)
+
+	return d.save()
+}
+
+// List returns urls of profile ordered by last failure, empty profile means all profiles
+func (d *DeadLetter) List(_ context.Context, profile string) ([]models.DeadLetter, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if err := d.load(); err != nil {
+		return nil, err
+	}
+	letters := make([]models.DeadLetter, 0, len(d.letters))
+	for _, letter := range d.letters {
+		if profile == "" || letter.Profile == profile {
+			letters = append(letters, letter)
+		}
+	}
+	sort.Slice(letters, func(i, j int) bool {
+		if !letters[i].LastFailed.Equal(letters[j].LastFailed) {
+			return letters[i].LastFailed.Before(letters[j].LastFailed)
+		}
+		return letters[i].Url < letters[j].Url
+	})
+
+	return letters, nil
+}
+
+func (d *DeadLetter) load() error {
+	if d.letters != nil {
+		return nil
+	}
+
+	letters := make([]models.DeadLetter, 0)
+	b, err := os.ReadFile(d.path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return errors.Wrap(err, "read dead letters")
+	default:
+		if err = json.Unmarshal(b, &letters); err != nil {
+			return errors.Wrap(err, "unmarshal dead letters")
+		}
+	}
+
+	d.letters = make(map[string]models.DeadLetter, len(letters))
+	for _, letter := range letters {
+		d.letters[letter.Url] = letter
+	}
+
+	return nil
+}
+
+// save writes letters to a temporary file renamed over the store, a crash keeps the previous file
+func (d *DeadLetter) save() error {
+	letters := make([]models.DeadLetter, 0, len(d.letters))
+	for _, letter := range d.letters {
+		letters = append(letters, letter)
+	}
+	sort.Slice(letters, func(i, j int) bool {
+		return letters[i].Url < letters[j].Url
+	})
+	b, err := json.MarshalIndent(letters, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshal dead letters")
+	}
+
+	if err = os.MkdirAll(filepath.Dir(d.path), 0o755); err != nil {
+		return errors.Wrap(err, "create dead letters dir")
+	}
+	tmp := d.path + ".tmp"
+	if err = os.WriteFile(tmp, append(b, '\n'), 0o644); err != nil {
+		return errors.Wrap(err, "write dead letters")
+	}
+	if err = os.Rename(tmp, d.path); err != nil {
+		return errors.Wrap(err, "rename dead letters")
+	}
+
+	return nil
+}
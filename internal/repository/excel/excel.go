@@ -1,58 +1,167 @@
 package excel
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"fmt"
 	"github.com/pkg/errors"
 	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/pkg/logger"
 	"github.com/xuri/excelize/v2"
 	"hash/crc32"
+	"io"
+	"net/url"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 const (
-	parserXlsFile   = "parser.xlsx"
-	parserXlsSheet1 = "Sheet1"
+	legacySheet     = "Sheet1"
+	summarySheet    = "Summary"
+	versionsSheet   = "Versions"
+	otherSheet      = "other"
+	unknownMonth    = "unknown"
+	hyperlinksLimit = 65530 // excel does not open sheets with more hyperlinks
+)
+
+var (
+	header = []interface{}{
+		"Url", "Title", "OverTitle", "Lead", "Subtitles", "ImageTitles", "Profile", "Date", "Author", "Analysis", "Raw",
+	}
+	columnWidths   = []float64{50, 50, 25, 80, 50, 80, 10, 22, 25, 40, 40}
+	versionsHeader = []interface{}{
+		"Url", "Replaced", "Date", "Author", "Title", "OverTitle", "Lead", "Subtitles", "ImageTitles",
+	}
 )
 
 type Excel struct {
-	parserFile *excelize.File
-	rowsCount  int
-	crcTable   *crc32.Table
+	path        string
+	backups     int
+	journal     *os.File
+	parserFile  *excelize.File
+	rowsCount   map[string]int
+	crcTable    *crc32.Table
+	changed     bool
+	rotated     bool
+	headerStyle int
+	textStyle   int
+	linkStyle   int
+	summary     map[string]map[string]int
 }
 
-func New() *Excel {
+// New opens workbook at path, keeps backups previous versions and replays unsaved rows from journal
+func New(path string, backups int) (*Excel, error) {
 	var f *excelize.File
-	_, err := os.Stat(parserXlsFile)
+	_, err := os.Stat(path)
 	if os.IsNotExist(err) {
 		f = excelize.NewFile()
-		_, _ = f.NewSheet(parserXlsSheet1)
-	} else {
-		f, _ = excelize.OpenFile(parserXlsFile)
+	} else if err != nil {
+		return nil, errors.Wrap(err, "stat excel file")
+	} else if f, err = excelize.OpenFile(path); err != nil {
+		return nil, errors.Wrap(err, "open excel file")
 	}
-	rows, _ := f.GetRows(parserXlsSheet1)
-	crcTable := crc32.MakeTable(crc32.IEEE)
 
-	return &Excel{
+	e := &Excel{
+		path:       path,
+		backups:    backups,
 		parserFile: f,
-		rowsCount:  len(rows),
-		crcTable:   crcTable,
+		rowsCount:  make(map[string]int),
+		crcTable:   crc32.MakeTable(crc32.IEEE),
+		summary:    make(map[string]map[string]int),
+	}
+	e.headerStyle, _ = f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"#DDEBF7"}},
+		Alignment: &excelize.Alignment{Vertical: "center"},
+	})
+	e.textStyle, _ = f.NewStyle(&excelize.Style{
+		Alignment: &excelize.Alignment{WrapText: true, Vertical: "top"},
+	})
+	e.linkStyle, _ = f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Color: "#1265BE", Underline: "single"},
+		Alignment: &excelize.Alignment{Vertical: "top"},
+	})
+
+	if _, err = f.NewSheet(summarySheet); err != nil {
+		return nil, errors.Wrap(err, "create summary sheet")
+	}
+	for _, sheet := range e.profileSheets() {
+		// header grows with new columns
+		_ = f.SetSheetRow(sheet, "A1", &header)
+		rows, _ := f.GetRows(sheet)
+		e.rowsCount[sheet] = len(rows)
+		for i, row := range rows {
+			if i > 0 {
+				e.count(sheet, parseDate(cell(row, 7)), 1)
+			}
+		}
 	}
+
+	if err = e.migrateLegacySheet(); err != nil {
+		return nil, errors.Wrap(err, "migrate legacy sheet")
+	}
+
+	if rows, err := f.GetRows(versionsSheet); err == nil {
+		e.rowsCount[versionsSheet] = len(rows)
+	}
+
+	if err = e.replayJournal(); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// migrateLegacySheet moves rows of the single unlabelled sheet into profile sheets
+func (e *Excel) migrateLegacySheet() error {
+	if idx, _ := e.parserFile.GetSheetIndex(legacySheet); idx < 0 {
+		return nil
+	}
+	rows, err := e.parserFile.GetRows(legacySheet)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if cell(row, 0) == "" {
+			continue
+		}
+		modelComplex := rowComplex(row)
+		if modelComplex.Profile == "" {
+			modelComplex.Profile = guessProfile(modelComplex.Url)
+		}
+		if _, err = e.setComplex(modelComplex); err != nil {
+			return err
+		}
+	}
+
+	return e.parserFile.DeleteSheet(legacySheet)
 }
 
 func (e *Excel) GetUsedUrls(context.Context) (map[uint32]*models.ExcelRow, error) {
 	ss := make(map[uint32]*models.ExcelRow, 0)
 
-	rows, err := e.parserFile.GetRows(parserXlsSheet1)
-	if err != nil {
-		return nil, errors.Wrap(err, "Get used urls")
-	}
+	for _, sheet := range e.profileSheets() {
+		rows, err := e.parserFile.GetRows(sheet)
+		if err != nil {
+			return nil, errors.Wrap(err, "Get used urls")
+		}
 
-	for i, row := range rows {
-		url := crc32.Checksum([]byte(row[0]), e.crcTable)
-		ss[url] = &models.ExcelRow{
-			Row: i + 1,
+		for i, row := range rows {
+			if i == 0 || cell(row, 0) == "" {
+				continue
+			}
+			url := crc32.Checksum([]byte(row[0]), e.crcTable)
+			ss[url] = &models.ExcelRow{
+				Sheet: sheet,
+				Row:   i + 1,
+			}
 		}
 	}
 
@@ -60,36 +169,536 @@ func (e *Excel) GetUsedUrls(context.Context) (map[uint32]*models.ExcelRow, error
 }
 
 func (e *Excel) SetComplex(ctx context.Context, modelComplex models.Complex) error {
-	n := 0
-	if modelComplex.ExcelRow != nil && modelComplex.ExcelRow.Row > 0 {
-		n = modelComplex.ExcelRow.Row
+	if err := e.writeJournal(modelComplex); err != nil {
+		return err
+	}
+	if _, err := e.setComplex(modelComplex); err != nil {
+		return err
+	}
+
+	if checkEverySave() {
+		if err := e.save(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetComplexes writes complexes with one journal sync and saves the workbook once
+func (e *Excel) SetComplexes(ctx context.Context, complexes []models.Complex) error {
+	if err := e.writeJournal(complexes...); err != nil {
+		return err
+	}
+	for _, modelComplex := range complexes {
+		if _, err := e.setComplex(modelComplex); err != nil {
+			return err
+		}
+	}
+
+	return e.save()
+}
+
+// setComplex writes complex to its row or appends it to the profile sheet, returns the written row
+func (e *Excel) setComplex(modelComplex models.Complex) (*models.ExcelRow, error) {
+	analysis, err := formatAnalysis(modelComplex.Analysis)
+	if err != nil {
+		return nil, err
+	}
+	values := []string{
+		modelComplex.Url,
+		modelComplex.Title,
+		modelComplex.OverTitle,
+		modelComplex.Lead,
+		strings.Join(modelComplex.Subtitles, "\n"),
+		strings.Join(modelComplex.ImageTitles, "\n"),
+		modelComplex.Profile,
+		formatDate(modelComplex.Date),
+		modelComplex.Author,
+		analysis,
+		formatRaw(modelComplex.Raw),
+	}
+	sheet, n := "", 0
+	if modelComplex.ExcelRow != nil && modelComplex.ExcelRow.Row > 0 && modelComplex.ExcelRow.Sheet != "" {
+		sheet, n = modelComplex.ExcelRow.Sheet, modelComplex.ExcelRow.Row
+		// rewriting the same values, like replaying a journal of saved rows, leaves the workbook unchanged
+		if e.rowEquals(sheet, n, values) {
+			return &models.ExcelRow{
+				Sheet: sheet,
+				Row:   n,
+			}, nil
+		}
+		if err := e.keepVersion(sheet, n, modelComplex); err != nil {
+			return nil, err
+		}
+		// the updated date may move the article to another month of the summary
+		prevDate, _ := e.parserFile.GetCellValue(sheet, "H"+strconv.Itoa(n))
+		e.count(sheet, parseDate(prevDate), -1)
+		e.count(sheet, modelComplex.Date, 1)
 	} else {
-		e.rowsCount++
-		n = e.rowsCount
+		if sheet, err = e.profileSheet(modelComplex.Profile); err != nil {
+			return nil, err
+		}
+		e.rowsCount[sheet]++
+		n = e.rowsCount[sheet]
+		e.count(sheet, modelComplex.Date, 1)
+	}
+
+	row := strconv.Itoa(n)
+	cells := make([]interface{}, len(values))
+	for i, value := range values {
+		cells[i] = value
+	}
+	if err = e.parserFile.SetSheetRow(sheet, "A"+row, &cells); err != nil {
+		return nil, err
+	}
+	if n <= hyperlinksLimit && modelComplex.Url != "" {
+		_ = e.parserFile.SetCellHyperLink(sheet, "A"+row, modelComplex.Url, "External")
+		_ = e.parserFile.SetCellStyle(sheet, "A"+row, "A"+row, e.linkStyle)
+	}
+	e.changed = true
+
+	return &models.ExcelRow{
+		Sheet: sheet,
+		Row:   n,
+	}, nil
+}
+
+// rowEquals reports whether row n of sheet holds values
+func (e *Excel) rowEquals(sheet string, n int, values []string) bool {
+	for i, value := range values {
+		col, _ := excelize.ColumnNumberToName(i + 1)
+		if current, _ := e.parserFile.GetCellValue(sheet, col+strconv.Itoa(n)); current != value {
+			return false
+		}
 	}
-	err := e.parserFile.SetCellValue(parserXlsSheet1, "A"+strconv.Itoa(n), modelComplex.Url)
+
+	return true
+}
+
+// keepVersion appends text of the row to the versions sheet when complex changes it
+func (e *Excel) keepVersion(sheet string, n int, modelComplex models.Complex) error {
+	row := make([]string, len(header))
+	for i := range row {
+		col, _ := excelize.ColumnNumberToName(i + 1)
+		row[i], _ = e.parserFile.GetCellValue(sheet, col+strconv.Itoa(n))
+	}
+	prev := rowComplex(row)
+	version := models.NewVersion(prev, time.Now())
+	if prev.Url != modelComplex.Url || !version.Changed(modelComplex) {
+		return nil
+	}
+
+	f := e.parserFile
+	if idx, _ := f.GetSheetIndex(versionsSheet); idx < 0 {
+		if _, err := f.NewSheet(versionsSheet); err != nil {
+			return errors.Wrap(err, "create versions sheet")
+		}
+		if err := f.SetSheetRow(versionsSheet, "A1", &versionsHeader); err != nil {
+			return err
+		}
+		_ = f.SetRowStyle(versionsSheet, 1, 1, e.headerStyle)
+		_ = f.SetColWidth(versionsSheet, "A", "A", 50)
+		_ = f.SetColWidth(versionsSheet, "B", "D", 22)
+		_ = f.SetColWidth(versionsSheet, "E", "I", 50)
+		e.rowsCount[versionsSheet] = 1
+	}
+	e.rowsCount[versionsSheet]++
+
+	return f.SetSheetRow(versionsSheet, "A"+strconv.Itoa(e.rowsCount[versionsSheet]), &[]interface{}{
+		prev.Url,
+		formatDate(version.Replaced),
+		formatDate(version.Date),
+		version.Author,
+		version.Title,
+		version.OverTitle,
+		version.Lead,
+		strings.Join(version.Subtitles, "\n"),
+		strings.Join(version.ImageTitles, "\n"),
+	})
+}
+
+// GetComplex returns complex of article id, all rows are read
+func (e *Excel) GetComplex(ctx context.Context, id string) (models.Complex, error) {
+	complexes, err := e.GetComplexes(ctx)
+	if err != nil {
+		return models.Complex{}, err
+	}
+
+	return models.FindComplex(complexes, id)
+}
+
+// GetVersions returns replaced versions of url from oldest
+func (e *Excel) GetVersions(_ context.Context, url string) ([]models.Version, error) {
+	versions := make([]models.Version, 0)
+	if idx, _ := e.parserFile.GetSheetIndex(versionsSheet); idx < 0 {
+		return versions, nil
+	}
+	rows, err := e.parserFile.GetRows(versionsSheet)
 	if err != nil {
+		return nil, errors.Wrap(err, "Get versions")
+	}
+
+	for i, row := range rows {
+		if i == 0 || cell(row, 0) != url {
+			continue
+		}
+		versions = append(versions, models.Version{
+			Replaced:    parseDate(cell(row, 1)),
+			Date:        parseDate(cell(row, 2)),
+			Author:      cell(row, 3),
+			Title:       cell(row, 4),
+			OverTitle:   cell(row, 5),
+			Lead:        cell(row, 6),
+			Subtitles:   splitList(cell(row, 7)),
+			ImageTitles: splitList(cell(row, 8)),
+		})
+	}
+
+	return versions, nil
+}
+
+// profileSheet returns sheet of profile, the sheet is created with header when missing
+func (e *Excel) profileSheet(profile string) (string, error) {
+	sheet := profile
+	if sheet == "" || sheet == summarySheet || sheet == legacySheet || sheet == versionsSheet {
+		sheet = otherSheet
+	}
+	if idx, _ := e.parserFile.GetSheetIndex(sheet); idx >= 0 {
+		return sheet, nil
+	}
+
+	f := e.parserFile
+	if _, err := f.NewSheet(sheet); err != nil {
+		return "", errors.Wrap(err, "create sheet")
+	}
+	if err := f.SetSheetRow(sheet, "A1", &header); err != nil {
+		return "", err
+	}
+	for i, width := range columnWidths {
+		col, _ := excelize.ColumnNumberToName(i + 1)
+		_ = f.SetColWidth(sheet, col, col, width)
+	}
+	_ = f.SetColStyle(sheet, "B:K", e.textStyle)
+	_ = f.SetRowStyle(sheet, 1, 1, e.headerStyle)
+	_ = f.SetPanes(sheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	})
+	e.rowsCount[sheet] = 1
+
+	return sheet, nil
+}
+
+func (e *Excel) profileSheets() []string {
+	sheets := make([]string, 0)
+	for _, sheet := range e.parserFile.GetSheetList() {
+		if sheet != summarySheet && sheet != legacySheet && sheet != versionsSheet {
+			sheets = append(sheets, sheet)
+		}
+	}
+
+	return sheets
+}
+
+// count adds delta to the summary count of month of date, months without articles are dropped
+func (e *Excel) count(sheet string, date time.Time, delta int) {
+	month := unknownMonth
+	if !date.IsZero() {
+		month = date.Format("2006-01")
+	}
+	if _, ok := e.summary[sheet]; !ok {
+		e.summary[sheet] = make(map[string]int)
+	}
+	e.summary[sheet][month] += delta
+	if e.summary[sheet][month] <= 0 {
+		delete(e.summary[sheet], month)
+	}
+}
+
+// writeSummary writes article counts per month and outlet
+func (e *Excel) writeSummary() error {
+	f := e.parserFile
+	sheets := e.profileSheets()
+	sort.Strings(sheets)
+
+	monthSet := make(map[string]struct{})
+	for _, months := range e.summary {
+		for month := range months {
+			monthSet[month] = struct{}{}
+		}
+	}
+	months := make([]string, 0, len(monthSet))
+	for month := range monthSet {
+		months = append(months, month)
+	}
+	sort.Strings(months)
+
+	head := []interface{}{"Month"}
+	for _, sheet := range sheets {
+		head = append(head, sheet)
+	}
+	head = append(head, "Total")
+	if err := f.SetSheetRow(summarySheet, "A1", &head); err != nil {
 		return err
 	}
-	_ = e.parserFile.SetCellValue(parserXlsSheet1, "B"+strconv.Itoa(n), modelComplex.Title)
-	_ = e.parserFile.SetCellValue(parserXlsSheet1, "C"+strconv.Itoa(n), modelComplex.OverTitle)
-	_ = e.parserFile.SetCellValue(parserXlsSheet1, "D"+strconv.Itoa(n), modelComplex.Lead)
-	_ = e.parserFile.SetCellValue(parserXlsSheet1, "E"+strconv.Itoa(n), strings.Join(modelComplex.Subtitles, "\n"))
-	_ = e.parserFile.SetCellValue(parserXlsSheet1, "F"+strconv.Itoa(n), strings.Join(modelComplex.ImageTitles, "\n"))
 
-	if checkEverySave() {
-		if err = e.parserFile.SaveAs(parserXlsFile); err != nil {
+	totals := make([]interface{}, len(sheets)+2)
+	totals[0] = "Total"
+	for i, month := range months {
+		row := []interface{}{month}
+		total := 0
+		for j, sheet := range sheets {
+			c := e.summary[sheet][month]
+			row = append(row, c)
+			total += c
+			t, _ := totals[j+1].(int)
+			totals[j+1] = t + c
+		}
+		row = append(row, total)
+		t, _ := totals[len(totals)-1].(int)
+		totals[len(totals)-1] = t + total
+		if err := f.SetSheetRow(summarySheet, "A"+strconv.Itoa(i+2), &row); err != nil {
 			return err
 		}
 	}
+	last := strconv.Itoa(len(months) + 2)
+	if err := f.SetSheetRow(summarySheet, "A"+last, &totals); err != nil {
+		return err
+	}
+	// rows left by months whose articles moved to other months are removed
+	if rows, err := f.GetRows(summarySheet); err == nil {
+		for n := len(rows); n > len(months)+2; n-- {
+			_ = f.RemoveRow(summarySheet, n)
+		}
+	}
+
+	lastCol, _ := excelize.ColumnNumberToName(len(head))
+	_ = f.SetCellStyle(summarySheet, "A1", lastCol+"1", e.headerStyle)
+	_ = f.SetCellStyle(summarySheet, "A"+last, lastCol+last, e.headerStyle)
+	_ = f.SetColWidth(summarySheet, "A", lastCol, 14)
 
 	return nil
 }
 
-func (e *Excel) Close() error {
-	if err := e.parserFile.SaveAs(parserXlsFile); err != nil {
+// save writes workbook to temp file and renames it over the previous version,
+// so a crash never leaves a half written workbook behind
+func (e *Excel) save() error {
+	if !e.changed {
+		return e.truncateJournal()
+	}
+	if err := e.writeSummary(); err != nil {
+		return errors.Wrap(err, "write summary")
+	}
+
+	dir := filepath.Dir(e.path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(e.path)+".tmp-*")
+	if err != nil {
+		return errors.Wrap(err, "create temp file")
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err = e.parserFile.WriteTo(tmp); err != nil {
+		_ = tmp.Close()
+		return errors.Wrap(err, "write temp file")
+	}
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return errors.Wrap(err, "sync temp file")
+	}
+	if err = tmp.Close(); err != nil {
+		return errors.Wrap(err, "close temp file")
+	}
+
+	if err = e.rotateBackups(); err != nil {
+		return errors.Wrap(err, "rotate backups")
+	}
+	if err = os.Rename(tmp.Name(), e.path); err != nil {
+		return errors.Wrap(err, "rename temp file")
+	}
+	syncDir(dir)
+	e.changed = false
+
+	return e.truncateJournal()
+}
+
+// rotateBackups shifts path.1 .. path.N and links current workbook as path.1 on the first save of a run,
+// so backups keep the workbook as it was before each of the last N runs that changed it
+func (e *Excel) rotateBackups() error {
+	if e.backups <= 0 || e.rotated {
+		return nil
+	}
+	e.rotated = true
+	if _, err := os.Stat(e.path); os.IsNotExist(err) {
+		return nil
+	}
+
+	backup := func(n int) string {
+		return e.path + "." + strconv.Itoa(n)
+	}
+	_ = os.Remove(backup(e.backups))
+	for n := e.backups - 1; n > 0; n-- {
+		if err := os.Rename(backup(n), backup(n+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Link(e.path, backup(1)); err != nil {
+		return copyFile(e.path, backup(1))
+	}
+
+	return nil
+}
+
+func (e *Excel) journalPath() string {
+	return e.path + ".journal"
+}
+
+// writeJournal appends complexes to the write-ahead journal and syncs it to disk
+func (e *Excel) writeJournal(complexes ...models.Complex) error {
+	if e.journal == nil {
+		f, err := os.OpenFile(e.journalPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return errors.Wrap(err, "open journal")
+		}
+		e.journal = f
+	}
+
+	w := bufio.NewWriter(e.journal)
+	for _, modelComplex := range complexes {
+		b, err := json.Marshal(modelComplex)
+		if err != nil {
+			return errors.Wrap(err, "marshal journal entry")
+		}
+		if _, err = w.Write(append(b, '\n')); err != nil {
+			return errors.Wrap(err, "write journal")
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return errors.Wrap(err, "write journal")
+	}
+	if err := e.journal.Sync(); err != nil {
+		return errors.Wrap(err, "sync journal")
+	}
+
+	return nil
+}
+
+func (e *Excel) truncateJournal() error {
+	if e.journal == nil {
+		return nil
+	}
+	if err := e.journal.Truncate(0); err != nil {
+		return errors.Wrap(err, "truncate journal")
+	}
+
+	return e.journal.Sync()
+}
+
+// replayJournal applies rows written after the last successful save
+func (e *Excel) replayJournal() error {
+	f, err := os.Open(e.journalPath())
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "open journal")
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	used, err := e.GetUsedUrls(context.Background())
+	if err != nil {
 		return err
 	}
+
+	replayed := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var modelComplex models.Complex
+		if err = json.Unmarshal(scanner.Bytes(), &modelComplex); err != nil {
+			// torn write of the last entry
+			break
+		}
+		// the journal may outlive a save that already contains its rows
+		url := crc32.Checksum([]byte(modelComplex.Url), e.crcTable)
+		if excelRow, ok := used[url]; ok {
+			modelComplex.ExcelRow = excelRow
+		}
+		excelRow, err := e.setComplex(modelComplex)
+		if err != nil {
+			return errors.Wrap(err, "replay journal")
+		}
+		used[url] = excelRow
+		replayed++
+	}
+
+	if replayed > 0 && e.changed {
+		logger.Get().Infof("Replayed %d rows from journal %s", replayed, e.journalPath())
+		if err = e.save(); err != nil {
+			return err
+		}
+	}
+
+	return os.Remove(e.journalPath())
+}
+
+func (e *Excel) GetComplexes(context.Context) ([]models.Complex, error) {
+	complexes := make([]models.Complex, 0)
+	for _, sheet := range e.profileSheets() {
+		rows, err := e.parserFile.GetRows(sheet)
+		if err != nil {
+			return nil, errors.Wrap(err, "Get complexes")
+		}
+
+		for i, row := range rows {
+			if i == 0 || cell(row, 0) == "" {
+				continue
+			}
+			modelComplex := rowComplex(row)
+			modelComplex.ExcelRow = &models.ExcelRow{
+				Sheet: sheet,
+				Row:   i + 1,
+			}
+			complexes = append(complexes, modelComplex)
+		}
+	}
+
+	return complexes, nil
+}
+
+// Search returns complexes containing phrase in fields, case-insensitive
+func (e *Excel) Search(ctx context.Context, phrase string, fields []models.Field) ([]models.Complex, error) {
+	complexes, err := e.GetComplexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	phrase = strings.ToLower(phrase)
+	found := make([]models.Complex, 0)
+	for _, modelComplex := range complexes {
+		if modelComplex.ContainsPhrase(phrase, fields) {
+			found = append(found, modelComplex)
+		}
+	}
+
+	return found, nil
+}
+
+func (e *Excel) Close() error {
+	if e.changed {
+		if err := e.save(); err != nil {
+			return err
+		}
+	}
+	if e.journal != nil {
+		_ = e.journal.Close()
+		_ = os.Remove(e.journalPath())
+	}
 	if err := e.parserFile.Close(); err != nil {
 		return err
 	}
@@ -97,6 +706,174 @@ func (e *Excel) Close() error {
 	return nil
 }
 
+func rowComplex(row []string) models.Complex {
+	return models.Complex{
+		Title:       cell(row, 1),
+		OverTitle:   cell(row, 2),
+		Lead:        cell(row, 3),
+		Subtitles:   splitList(cell(row, 4)),
+		ImageTitles: splitList(cell(row, 5)),
+		Profile:     cell(row, 6),
+		Date:        parseDate(cell(row, 7)),
+		Author:      cell(row, 8),
+		Analysis:    parseAnalysis(cell(row, 9)),
+		Raw:         parseRaw(cell(row, 10)),
+		ExcelUrl: models.ExcelUrl{
+			Url: cell(row, 0),
+		},
+	}
+}
+
+func guessProfile(rawUrl string) string {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case strings.HasSuffix(u.Host, "zeit.de"):
+		return "zeit"
+	case strings.HasSuffix(u.Host, "spiegel.de"):
+		return "spiegel"
+	}
+
+	return ""
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+// syncDir flushes directory entry after rename, not supported on every platform
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	_ = d.Close()
+}
+
+func cell(row []string, n int) string {
+	if n < len(row) {
+		return row[n]
+	}
+
+	return ""
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+func parseDate(s string) time.Time {
+	t, _ := time.Parse(time.RFC3339, s)
+
+	return t
+}
+
+// formatAnalysis encodes analysis within the cell limit of excel, entities are dropped first and
+// found again by stats, then the longest word list is halved until the analysis fits, counts are kept
+func formatAnalysis(a models.Analysis) (string, error) {
+	b, _ := json.Marshal(a)
+	if utf8.RuneCount(b) <= excelize.TotalCellChars {
+		return string(b), nil
+	}
+
+	a.Entities = nil
+	lexicon, language := models.Lexicon{}, models.Language{}
+	if a.Lexicon != nil {
+		lexicon = *a.Lexicon
+		a.Lexicon = &lexicon
+	}
+	if a.Language != nil {
+		language = *a.Language
+		a.Language = &language
+	}
+	for {
+		if b, _ = json.Marshal(a); utf8.RuneCount(b) <= excelize.TotalCellChars {
+			return string(b), nil
+		}
+		longest := []int{len(lexicon.Compounds), len(lexicon.Anglicisms), len(language.Subtitles), len(language.ImageTitles)}
+		i := 0
+		for j := range longest {
+			if longest[j] > longest[i] {
+				i = j
+			}
+		}
+		switch n := longest[i] / 2; {
+		case longest[i] == 0:
+			return "", errors.New(fmt.Sprintf("analysis of %d chars exceeds cell limit", utf8.RuneCount(b)))
+		case i == 0:
+			lexicon.Compounds = lexicon.Compounds[:n]
+		case i == 1:
+			lexicon.Anglicisms = lexicon.Anglicisms[:n]
+		case i == 2:
+			language.Subtitles = language.Subtitles[:n]
+		default:
+			language.ImageTitles = language.ImageTitles[:n]
+		}
+	}
+}
+
+func parseAnalysis(s string) models.Analysis {
+	a := models.Analysis{}
+	if s != "" {
+		_ = json.Unmarshal([]byte(s), &a)
+	}
+
+	return a
+}
+
+func formatRaw(r *models.Raw) string {
+	if r == nil {
+		return ""
+	}
+	b, _ := json.Marshal(r)
+
+	return string(b)
+}
+
+func parseRaw(s string) *models.Raw {
+	if s == "" {
+		return nil
+	}
+	r := &models.Raw{}
+	if err := json.Unmarshal([]byte(s), r); err != nil {
+		return nil
+	}
+
+	return r
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+
+	return strings.Split(s, "\n")
+}
+
 var checkEverySave = func() func() bool {
 	c := -1
 	return func() bool {
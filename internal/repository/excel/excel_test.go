@@ -0,0 +1,156 @@
+package excel
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/sku4/mslu-parser/models"
+	"github.com/xuri/excelize/v2"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestFormatAnalysis(t *testing.T) {
+	long := models.Analysis{
+		Headline:    &models.Headline{Question: true, Words: 5},
+		Readability: &models.Readability{Words: 300},
+		Fingerprint: &models.Fingerprint{SimHash: "00ff00ff00ff00ff"},
+		Lexicon:     &models.Lexicon{Words: 9000},
+		Entities:    make([]models.Entity, 0),
+	}
+	for i := 0; i < 2000; i++ {
+		long.Lexicon.Compounds = append(long.Lexicon.Compounds, models.Compound{
+			Word:  strings.Repeat("Bundes", 3) + "regierung",
+			Parts: []string{"Bundes", "Bundes", "Bundes", "Regierung"},
+		})
+		long.Lexicon.Anglicisms = append(long.Lexicon.Anglicisms, models.Anglicism{Word: "Meeting", Reason: "list"})
+		long.Entities = append(long.Entities, models.Entity{Type: models.EntityPerson, Name: "Olaf Scholz"})
+	}
+	short := models.Analysis{Headline: &models.Headline{Words: 3}, Entities: make([]models.Entity, 0)}
+
+	tests := []struct {
+		name     string
+		analysis models.Analysis
+		trimmed  bool
+	}{
+		{"within limit", short, false},
+		{"word lists over limit", long, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := formatAnalysis(tt.analysis)
+			if err != nil {
+				t.Fatalf("formatAnalysis() error = %v", err)
+			}
+			if utf8.RuneCountInString(s) > excelize.TotalCellChars {
+				t.Fatalf("formatAnalysis() length = %d, over cell limit", utf8.RuneCountInString(s))
+			}
+			got := models.Analysis{}
+			if err = json.Unmarshal([]byte(s), &got); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if got.Headline == nil || *got.Headline != *tt.analysis.Headline {
+				t.Errorf("headline = %v, want %v", got.Headline, tt.analysis.Headline)
+			}
+			if (got.Entities == nil) != tt.trimmed {
+				t.Errorf("entities dropped = %v, want %v", got.Entities == nil, tt.trimmed)
+			}
+			if !tt.trimmed {
+				return
+			}
+			if got.Readability == nil || got.Fingerprint == nil || got.Lexicon == nil {
+				t.Fatalf("formatAnalysis() dropped scores: %s", s)
+			}
+			if got.Lexicon.Words != tt.analysis.Lexicon.Words || len(got.Lexicon.Compounds) == 0 || len(got.Lexicon.Anglicisms) == 0 {
+				t.Errorf("lexicon = %d words, %d compounds, %d anglicisms, want counts kept and lists trimmed",
+					got.Lexicon.Words, len(got.Lexicon.Compounds), len(got.Lexicon.Anglicisms))
+			}
+			if len(tt.analysis.Lexicon.Compounds) != 2000 {
+				t.Error("formatAnalysis() changed lists of its argument")
+			}
+		})
+	}
+}
+
+func TestBackups(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "parser.xlsx")
+	// run opens the workbook and saves every title to the same article once
+	run := func(titles ...string) {
+		t.Helper()
+		e, err := New(path, 2)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		for _, title := range titles {
+			complexes, err := e.GetComplexes(ctx)
+			if err != nil {
+				t.Fatalf("GetComplexes() error = %v", err)
+			}
+			cx := models.Complex{ExcelUrl: models.ExcelUrl{Url: "https://example.com/a"}, Profile: "zeit", Title: title}
+			if len(complexes) > 0 {
+				cx.ExcelRow = complexes[0].ExcelRow
+			}
+			if err = e.SetComplexes(ctx, []models.Complex{cx}); err != nil {
+				t.Fatalf("SetComplexes() error = %v", err)
+			}
+		}
+		if err = e.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	}
+	// title returns title of the article in workbook at path, empty when there is no workbook
+	title := func(path string) string {
+		t.Helper()
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return ""
+		}
+		e, err := New(path, 0)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		defer func() {
+			_ = e.Close()
+		}()
+		complexes, err := e.GetComplexes(ctx)
+		if err != nil || len(complexes) != 1 {
+			t.Fatalf("GetComplexes() = %d complexes, error %v", len(complexes), err)
+		}
+
+		return complexes[0].Title
+	}
+	check := func(step string, want ...string) {
+		t.Helper()
+		got := []string{title(path), title(path + ".1"), title(path + ".2")}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: workbook and backups = %q, want %q", step, got, want)
+		}
+	}
+
+	run("a", "b")
+	check("first run", "b", "", "")
+	run("c", "d")
+	check("second run", "d", "b", "")
+	run("d")
+	check("unchanged run", "d", "b", "")
+	run()
+	check("read only run", "d", "b", "")
+
+	// journal of rows the workbook already holds
+	b, _ := json.Marshal(models.Complex{ExcelUrl: models.ExcelUrl{Url: "https://example.com/a"}, Profile: "zeit", Title: "d"})
+	if err := os.WriteFile(path+".journal", append(b, '\n'), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run()
+	check("replayed saved journal", "d", "b", "")
+	if _, err := os.Stat(path + ".journal"); !os.IsNotExist(err) {
+		t.Errorf("journal left after replay, stat error = %v", err)
+	}
+
+	run("e")
+	check("third run", "e", "d", "b")
+}
@@ -0,0 +1,92 @@
+package failures
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/sku4/mslu-parser/models"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Failures keeps snapshots of failed articles in a directory, <profile>-<crc>.json holds
+// status, headers and error, <profile>-<crc>.html the response body
+type Failures struct {
+	dir string
+}
+
+func New(dir string) *Failures {
+	return &Failures{
+		dir: dir,
+	}
+}
+
+func (f *Failures) Save(_ context.Context, snapshot models.Snapshot) error {
+	if err := os.MkdirAll(f.dir, 0o755); err != nil {
+		return errors.Wrap(err, "create failures dir")
+	}
+	name := f.name(snapshot.Profile, snapshot.Url)
+	if err := os.WriteFile(name+".html", snapshot.Html, 0o644); err != nil {
+		return errors.Wrap(err, "write snapshot html")
+	}
+	b, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshal snapshot")
+	}
+	if err = os.WriteFile(name+".json", append(b, '\n'), 0o644); err != nil {
+		return errors.Wrap(err, "write snapshot")
+	}
+
+	return nil
+}
+
+// List returns snapshots of profile without html ordered by time, empty profile means all profiles
+func (f *Failures) List(_ context.Context, profile string) ([]models.Snapshot, error) {
+	paths, err := filepath.Glob(filepath.Join(f.dir, "*.json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "list failures")
+	}
+
+	snapshots := make([]models.Snapshot, 0, len(paths))
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "read snapshot")
+		}
+		var snapshot models.Snapshot
+		if err = json.Unmarshal(b, &snapshot); err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("unmarshal snapshot %s", path))
+		}
+		if profile != "" && snapshot.Profile != profile {
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Time.Before(snapshots[j].Time)
+	})
+
+	return snapshots, nil
+}
+
+// Remove deletes snapshot of url, missing snapshot is not an error
+func (f *Failures) Remove(_ context.Context, profile, url string) error {
+	name := f.name(profile, url)
+	for _, ext := range []string{".json", ".html"} {
+		if err := os.Remove(name + ext); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "remove snapshot")
+		}
+	}
+
+	return nil
+}
+
+func (f *Failures) name(profile, url string) string {
+	profile = strings.ReplaceAll(profile, string(filepath.Separator), "_")
+
+	return filepath.Join(f.dir, fmt.Sprintf("%s-%08x", profile, crc32.ChecksumIEEE([]byte(url))))
+}
@@ -2,7 +2,12 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/sku4/mslu-parser/internal/repository/deadletter"
 	"github.com/sku4/mslu-parser/internal/repository/excel"
+	"github.com/sku4/mslu-parser/internal/repository/failures"
+	"github.com/sku4/mslu-parser/internal/repository/sqlite"
 	"github.com/sku4/mslu-parser/models"
 )
 
@@ -11,15 +16,69 @@ import (
 type Excel interface {
 	GetUsedUrls(context.Context) (map[uint32]*models.ExcelRow, error)
 	SetComplex(context.Context, models.Complex) error
+	SetComplexes(context.Context, []models.Complex) error
+	GetComplexes(context.Context) ([]models.Complex, error)
+	GetComplex(ctx context.Context, id string) (models.Complex, error)
+	Search(ctx context.Context, phrase string, fields []models.Field) ([]models.Complex, error)
+	GetVersions(ctx context.Context, url string) ([]models.Version, error)
 	Close() error
 }
 
+type Failures interface {
+	Save(context.Context, models.Snapshot) error
+	List(ctx context.Context, profile string) ([]models.Snapshot, error)
+	Remove(ctx context.Context, profile, url string) error
+}
+
+type DeadLetters interface {
+	Get(ctx context.Context, url string) (*models.DeadLetter, error)
+	Put(context.Context, models.DeadLetter) error
+	Remove(ctx context.Context, url string) error
+	List(ctx context.Context, profile string) ([]models.DeadLetter, error)
+}
+
 type Repository struct {
 	Excel
+	Failures    Failures
+	DeadLetters DeadLetters
+}
+
+type Config struct {
+	Storage     string
+	Xlsx        string
+	Backups     int
+	Sqlite      string
+	Failures    string
+	DeadLetters string
 }
 
-func NewRepository() *Repository {
-	return &Repository{
-		Excel: excel.New(),
+func NewRepository(cfg Config) (*Repository, error) {
+	var (
+		storage Excel
+		err     error
+	)
+	switch cfg.Storage {
+	case "", "excel":
+		storage, err = excel.New(cfg.Xlsx, cfg.Backups)
+	case "sqlite":
+		storage, err = sqlite.New(cfg.Sqlite)
+	default:
+		return nil, errors.New(fmt.Sprintf("Storage '%s' not found", cfg.Storage))
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	repos := &Repository{
+		Excel: storage,
+	}
+	// snapshots of failed articles are not kept without directory
+	if cfg.Failures != "" {
+		repos.Failures = failures.New(cfg.Failures)
+	}
+	if cfg.DeadLetters != "" {
+		repos.DeadLetters = deadletter.New(cfg.DeadLetters)
+	}
+
+	return repos, nil
 }
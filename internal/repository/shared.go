@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"github.com/sku4/mslu-parser/internal/repository/excel"
+	"github.com/sku4/mslu-parser/models"
+	"strings"
+	"sync"
+	"time"
+)
+
+// shared guards storage used by concurrent requests and crawl jobs, writes exclude reads
+type shared struct {
+	storage Excel
+	rwMutex *sync.RWMutex
+	cache   *cache
+}
+
+// cacheMaxAge is how long reads keep complexes loaded before later writes, so that reads during
+// a crawl reload the workbook at most this often and may miss articles saved meanwhile
+const cacheMaxAge = 30 * time.Second
+
+// cache keeps complexes of storage until writes make it stale, reading all rows of a workbook takes seconds
+type cache struct {
+	mutex     sync.Mutex
+	loaded    bool
+	stale     bool
+	loadedAt  time.Time
+	complexes []models.Complex
+	byID      map[string]int
+}
+
+// NewShared returns repository over storage of repos safe for concurrent use, its Close
+// does nothing and the storage is closed by the owner of repos
+func NewShared(repos *Repository) *Repository {
+	return &Repository{
+		Excel: shared{
+			storage: repos.Excel,
+			rwMutex: &sync.RWMutex{},
+			cache:   &cache{},
+		},
+		Failures:    repos.Failures,
+		DeadLetters: repos.DeadLetters,
+	}
+}
+
+func (s shared) GetUsedUrls(ctx context.Context) (map[uint32]*models.ExcelRow, error) {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	return s.storage.GetUsedUrls(ctx)
+}
+
+func (s shared) SetComplex(ctx context.Context, modelComplex models.Complex) error {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	s.invalidate()
+
+	return s.storage.SetComplex(ctx, modelComplex)
+}
+
+func (s shared) SetComplexes(ctx context.Context, complexes []models.Complex) error {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	s.invalidate()
+
+	return s.storage.SetComplexes(ctx, complexes)
+}
+
+// GetComplexes returns copy of cached complexes, values of complexes are shared and must not be changed
+func (s shared) GetComplexes(ctx context.Context) ([]models.Complex, error) {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	complexes, _, err := s.complexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]models.Complex(nil), complexes...), nil
+}
+
+func (s shared) GetComplex(ctx context.Context, id string) (models.Complex, error) {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	complexes, byID, err := s.complexes(ctx)
+	if err != nil {
+		return models.Complex{}, err
+	}
+	i, ok := byID[id]
+	if !ok {
+		return models.Complex{}, models.ArticleNotFoundError
+	}
+
+	return complexes[i], nil
+}
+
+// Search answers workbook searches from the cache, they scan all rows anyway,
+// other storages search their index
+func (s shared) Search(ctx context.Context, phrase string, fields []models.Field) ([]models.Complex, error) {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	if _, ok := s.storage.(*excel.Excel); !ok {
+		return s.storage.Search(ctx, phrase, fields)
+	}
+	complexes, _, err := s.complexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	phrase = strings.ToLower(phrase)
+	found := make([]models.Complex, 0)
+	for _, modelComplex := range complexes {
+		if modelComplex.ContainsPhrase(phrase, fields) {
+			found = append(found, modelComplex)
+		}
+	}
+
+	return found, nil
+}
+
+func (s shared) GetVersions(ctx context.Context, url string) ([]models.Version, error) {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	return s.storage.GetVersions(ctx, url)
+}
+
+func (s shared) Close() error {
+	return nil
+}
+
+// invalidate marks cache stale, callers hold write lock
+func (s shared) invalidate() {
+	s.cache.mutex.Lock()
+	s.cache.stale = true
+	s.cache.mutex.Unlock()
+}
+
+// complexes loads cache from storage, callers hold read lock
+func (s shared) complexes(ctx context.Context) ([]models.Complex, map[string]int, error) {
+	s.cache.mutex.Lock()
+	defer s.cache.mutex.Unlock()
+
+	if !s.cache.loaded || s.cache.stale && time.Since(s.cache.loadedAt) >= cacheMaxAge {
+		complexes, err := s.storage.GetComplexes(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		byID := make(map[string]int, len(complexes))
+		for i := range complexes {
+			byID[models.ArticleID(complexes[i].Url)] = i
+		}
+		s.cache.loaded, s.cache.stale, s.cache.loadedAt = true, false, time.Now()
+		s.cache.complexes, s.cache.byID = complexes, byID
+	}
+
+	return s.cache.complexes, s.cache.byID, nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"github.com/sku4/mslu-parser/models"
+	"testing"
+	"time"
+)
+
+// countingExcel counts loads of all complexes
+type countingExcel struct {
+	Excel
+	loads int
+}
+
+func (e *countingExcel) GetComplexes(context.Context) ([]models.Complex, error) {
+	e.loads++
+
+	return []models.Complex{}, nil
+}
+
+func (e *countingExcel) SetComplex(context.Context, models.Complex) error {
+	return nil
+}
+
+func TestSharedCache(t *testing.T) {
+	ctx := context.Background()
+	storage := &countingExcel{}
+	repos := NewShared(&Repository{Excel: storage})
+	s := repos.Excel.(shared)
+
+	read := func(step string, loads int) {
+		t.Helper()
+		if _, err := repos.GetComplexes(ctx); err != nil {
+			t.Fatalf("GetComplexes() error = %v", err)
+		}
+		if storage.loads != loads {
+			t.Errorf("%s: storage loads = %d, want %d", step, storage.loads, loads)
+		}
+	}
+
+	read("first read", 1)
+	read("read without writes", 1)
+	for i := 0; i < 10; i++ {
+		_ = repos.SetComplex(ctx, models.Complex{})
+		read("read during writes", 1)
+	}
+
+	s.cache.loadedAt = time.Now().Add(-cacheMaxAge)
+	read("read after max age", 2)
+	read("read after reload", 2)
+
+	s.cache.loadedAt = time.Now().Add(-cacheMaxAge)
+	read("old cache without writes", 2)
+}
@@ -0,0 +1,423 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/sku4/mslu-parser/models"
+	"hash/crc32"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	_ "modernc.org/sqlite"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS articles (
+	id          INTEGER PRIMARY KEY,
+	url         TEXT NOT NULL UNIQUE,
+	profile     TEXT NOT NULL DEFAULT '',
+	date        TEXT NOT NULL DEFAULT '',
+	author      TEXT NOT NULL DEFAULT '',
+	title       TEXT NOT NULL DEFAULT '',
+	overtitle   TEXT NOT NULL DEFAULT '',
+	lead        TEXT NOT NULL DEFAULT '',
+	subtitles   TEXT NOT NULL DEFAULT '',
+	imagetitles TEXT NOT NULL DEFAULT '',
+	analysis    TEXT NOT NULL DEFAULT '{}',
+	raw         TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS articles_profile_date ON articles (profile, date);
+CREATE TABLE IF NOT EXISTS versions (
+	id          INTEGER PRIMARY KEY,
+	url         TEXT NOT NULL,
+	replaced    TEXT NOT NULL,
+	date        TEXT NOT NULL DEFAULT '',
+	author      TEXT NOT NULL DEFAULT '',
+	title       TEXT NOT NULL DEFAULT '',
+	overtitle   TEXT NOT NULL DEFAULT '',
+	lead        TEXT NOT NULL DEFAULT '',
+	subtitles   TEXT NOT NULL DEFAULT '',
+	imagetitles TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS versions_url ON versions (url, id);
+` + ftsSchema + `;
+CREATE TRIGGER IF NOT EXISTS articles_ai AFTER INSERT ON articles BEGIN
+	INSERT INTO articles_fts (rowid, title, overtitle, lead, subtitles, imagetitles)
+	VALUES (new.id, new.title, new.overtitle, new.lead, new.subtitles, new.imagetitles);
+END;
+CREATE TRIGGER IF NOT EXISTS articles_ad AFTER DELETE ON articles BEGIN
+	INSERT INTO articles_fts (articles_fts, rowid, title, overtitle, lead, subtitles, imagetitles)
+	VALUES ('delete', old.id, old.title, old.overtitle, old.lead, old.subtitles, old.imagetitles);
+END;
+CREATE TRIGGER IF NOT EXISTS articles_au AFTER UPDATE ON articles BEGIN
+	INSERT INTO articles_fts (articles_fts, rowid, title, overtitle, lead, subtitles, imagetitles)
+	VALUES ('delete', old.id, old.title, old.overtitle, old.lead, old.subtitles, old.imagetitles);
+	INSERT INTO articles_fts (rowid, title, overtitle, lead, subtitles, imagetitles)
+	VALUES (new.id, new.title, new.overtitle, new.lead, new.subtitles, new.imagetitles);
+END;`
+
+// ftsSchema indexes trigrams of text fields, so that search finds case-insensitive substrings like the workbook
+const ftsSchema = `
+CREATE VIRTUAL TABLE IF NOT EXISTS articles_fts USING fts5(
+	title, overtitle, lead, subtitles, imagetitles,
+	content='articles', content_rowid='id', tokenize='trigram'
+)`
+
+// minTrigram is the least phrase length found by the trigram index
+const minTrigram = 3
+
+const columns = "id, url, profile, date, author, title, overtitle, lead, subtitles, imagetitles, analysis, raw"
+
+// migrations add columns missing in databases created by previous versions
+var migrations = []struct {
+	column, definition string
+}{
+	{"analysis", "TEXT NOT NULL DEFAULT '{}'"},
+	{"raw", "TEXT NOT NULL DEFAULT ''"},
+}
+
+type Sqlite struct {
+	db       *sql.DB
+	crcTable *crc32.Table
+}
+
+func New(path string) (*Sqlite, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, errors.Wrap(err, "open sqlite")
+	}
+	// single writer, sqlite serializes writes anyway
+	db.SetMaxOpenConns(1)
+
+	for _, pragma := range []string{"PRAGMA journal_mode = WAL", "PRAGMA synchronous = NORMAL"} {
+		if _, err = db.Exec(pragma); err != nil {
+			_ = db.Close()
+			return nil, errors.Wrap(err, "set pragma")
+		}
+	}
+	if _, err = db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, errors.Wrap(err, "create schema")
+	}
+	if err = migrate(db); err != nil {
+		_ = db.Close()
+		return nil, errors.Wrap(err, "migrate schema")
+	}
+
+	return &Sqlite{
+		db:       db,
+		crcTable: crc32.MakeTable(crc32.IEEE),
+	}, nil
+}
+
+func migrate(db *sql.DB) error {
+	rows, err := db.Query("SELECT name FROM pragma_table_info('articles')")
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		existing[name] = true
+	}
+	_ = rows.Close()
+
+	for _, m := range migrations {
+		if existing[m.column] {
+			continue
+		}
+		if _, err = db.Exec("ALTER TABLE articles ADD COLUMN " + m.column + " " + m.definition); err != nil {
+			return err
+		}
+	}
+
+	// previous versions indexed words, triggers refer to the table by name and survive recreation
+	var fts string
+	if err = db.QueryRow("SELECT sql FROM sqlite_master WHERE name = 'articles_fts'").Scan(&fts); err != nil {
+		return err
+	}
+	if strings.Contains(fts, "trigram") {
+		return nil
+	}
+	for _, statement := range []string{
+		"DROP TABLE articles_fts",
+		ftsSchema,
+		"INSERT INTO articles_fts (articles_fts) VALUES ('rebuild')",
+	} {
+		if _, err = db.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Sqlite) GetUsedUrls(ctx context.Context) (map[uint32]*models.ExcelRow, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT id, url FROM articles")
+	if err != nil {
+		return nil, errors.Wrap(err, "Get used urls")
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	ss := make(map[uint32]*models.ExcelRow, 0)
+	for rows.Next() {
+		var (
+			id  int
+			url string
+		)
+		if err = rows.Scan(&id, &url); err != nil {
+			return nil, errors.Wrap(err, "Get used urls")
+		}
+		ss[crc32.Checksum([]byte(url), s.crcTable)] = &models.ExcelRow{
+			Row: id,
+		}
+	}
+
+	return ss, rows.Err()
+}
+
+func (s *Sqlite) SetComplex(ctx context.Context, modelComplex models.Complex) error {
+	return s.SetComplexes(ctx, []models.Complex{modelComplex})
+}
+
+// SetComplexes writes complexes in one transaction
+func (s *Sqlite) SetComplexes(ctx context.Context, complexes []models.Complex) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "Set complex")
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	for _, modelComplex := range complexes {
+		if err = setComplex(ctx, tx, modelComplex); err != nil {
+			return errors.Wrap(err, "Set complex")
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "Set complex")
+	}
+
+	return nil
+}
+
+func setComplex(ctx context.Context, tx *sql.Tx, modelComplex models.Complex) error {
+	date := ""
+	if !modelComplex.Date.IsZero() {
+		date = modelComplex.Date.Format(time.RFC3339)
+	}
+
+	analysis, err := json.Marshal(modelComplex.Analysis)
+	if err != nil {
+		return err
+	}
+
+	raw := ""
+	if modelComplex.Raw != nil {
+		b, err := json.Marshal(modelComplex.Raw)
+		if err != nil {
+			return err
+		}
+		raw = string(b)
+	}
+
+	if err = keepVersion(ctx, tx, modelComplex); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO articles (url, profile, date, author, title, overtitle, lead, subtitles, imagetitles, analysis, raw)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (url) DO UPDATE SET
+			profile = excluded.profile, date = excluded.date, author = excluded.author,
+			title = excluded.title, overtitle = excluded.overtitle, lead = excluded.lead,
+			subtitles = excluded.subtitles, imagetitles = excluded.imagetitles, analysis = excluded.analysis,
+			raw = excluded.raw`,
+		modelComplex.Url, modelComplex.Profile, date, modelComplex.Author, modelComplex.Title,
+		modelComplex.OverTitle, modelComplex.Lead, strings.Join(modelComplex.Subtitles, "\n"),
+		strings.Join(modelComplex.ImageTitles, "\n"), string(analysis), raw)
+
+	return err
+}
+
+// keepVersion inserts text of the stored article into versions when complex changes it
+func keepVersion(ctx context.Context, tx *sql.Tx, modelComplex models.Complex) error {
+	var (
+		date, subtitles, imageTitles string
+		prev                         models.Complex
+	)
+	err := tx.QueryRowContext(ctx, `
+		SELECT date, author, title, overtitle, lead, subtitles, imagetitles FROM articles WHERE url = ?`,
+		modelComplex.Url).Scan(&date, &prev.Author, &prev.Title, &prev.OverTitle, &prev.Lead, &subtitles, &imageTitles)
+	if err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	prev.Subtitles = splitList(subtitles)
+	prev.ImageTitles = splitList(imageTitles)
+
+	version := models.NewVersion(prev, time.Now())
+	if !version.Changed(modelComplex) {
+		return nil
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO versions (url, replaced, date, author, title, overtitle, lead, subtitles, imagetitles)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		modelComplex.Url, version.Replaced.Format(time.RFC3339), date, prev.Author, prev.Title, prev.OverTitle,
+		prev.Lead, subtitles, imageTitles)
+
+	return err
+}
+
+// GetComplex returns complex of article id, ids are hashes of urls and not indexed
+func (s *Sqlite) GetComplex(ctx context.Context, id string) (models.Complex, error) {
+	complexes, err := s.GetComplexes(ctx)
+	if err != nil {
+		return models.Complex{}, err
+	}
+
+	return models.FindComplex(complexes, id)
+}
+
+// GetVersions returns replaced versions of url from oldest
+func (s *Sqlite) GetVersions(ctx context.Context, url string) ([]models.Version, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT replaced, date, author, title, overtitle, lead, subtitles, imagetitles
+		FROM versions WHERE url = ? ORDER BY id`, url)
+	if err != nil {
+		return nil, errors.Wrap(err, "Get versions")
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	versions := make([]models.Version, 0)
+	for rows.Next() {
+		var (
+			replaced, date         string
+			subtitles, imageTitles string
+			version                models.Version
+		)
+		err = rows.Scan(&replaced, &date, &version.Author, &version.Title, &version.OverTitle, &version.Lead,
+			&subtitles, &imageTitles)
+		if err != nil {
+			return nil, errors.Wrap(err, "Get versions")
+		}
+		version.Replaced, _ = time.Parse(time.RFC3339, replaced)
+		version.Date, _ = time.Parse(time.RFC3339, date)
+		version.Subtitles = splitList(subtitles)
+		version.ImageTitles = splitList(imageTitles)
+		versions = append(versions, version)
+	}
+
+	return versions, rows.Err()
+}
+
+func (s *Sqlite) GetComplexes(ctx context.Context) ([]models.Complex, error) {
+	return s.query(ctx, "SELECT "+columns+" FROM articles ORDER BY id")
+}
+
+// Search returns complexes containing phrase in fields, case-insensitive like the workbook search,
+// the full-text index narrows down articles for phrases of at least minTrigram characters
+func (s *Sqlite) Search(ctx context.Context, phrase string, fields []models.Field) ([]models.Complex, error) {
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field.IsText() {
+			names = append(names, string(field))
+		}
+	}
+	if len(names) == 0 {
+		return []models.Complex{}, nil
+	}
+
+	var (
+		complexes []models.Complex
+		err       error
+	)
+	if utf8.RuneCountInString(phrase) < minTrigram {
+		complexes, err = s.GetComplexes(ctx)
+	} else {
+		match := fmt.Sprintf(`{%s} : "%s"`, strings.Join(names, " "), strings.ReplaceAll(phrase, `"`, `""`))
+		complexes, err = s.query(ctx, `
+			SELECT `+columns+` FROM articles
+			WHERE id IN (SELECT rowid FROM articles_fts WHERE articles_fts MATCH ?)
+			ORDER BY id`, match)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the index matches across joined list values and folds case slightly different
+	phrase = strings.ToLower(phrase)
+	found := make([]models.Complex, 0, len(complexes))
+	for _, modelComplex := range complexes {
+		if modelComplex.ContainsPhrase(phrase, fields) {
+			found = append(found, modelComplex)
+		}
+	}
+
+	return found, nil
+}
+
+func (s *Sqlite) query(ctx context.Context, query string, args ...interface{}) ([]models.Complex, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Get complexes")
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	complexes := make([]models.Complex, 0)
+	for rows.Next() {
+		var (
+			id                     int
+			date                   string
+			subtitles, imageTitles string
+			analysis, raw          string
+			modelComplex           models.Complex
+		)
+		err = rows.Scan(&id, &modelComplex.Url, &modelComplex.Profile, &date, &modelComplex.Author,
+			&modelComplex.Title, &modelComplex.OverTitle, &modelComplex.Lead, &subtitles, &imageTitles, &analysis, &raw)
+		if err != nil {
+			return nil, errors.Wrap(err, "Get complexes")
+		}
+		_ = json.Unmarshal([]byte(analysis), &modelComplex.Analysis)
+		if raw != "" {
+			modelComplex.Raw = &models.Raw{}
+			_ = json.Unmarshal([]byte(raw), modelComplex.Raw)
+		}
+		modelComplex.Date, _ = time.Parse(time.RFC3339, date)
+		modelComplex.Subtitles = splitList(subtitles)
+		modelComplex.ImageTitles = splitList(imageTitles)
+		modelComplex.ExcelRow = &models.ExcelRow{
+			Row: id,
+		}
+		complexes = append(complexes, modelComplex)
+	}
+
+	return complexes, rows.Err()
+}
+
+func (s *Sqlite) Close() error {
+	return s.db.Close()
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+
+	return strings.Split(s, "\n")
+}
@@ -0,0 +1,110 @@
+package analyzer
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/sku4/mslu-parser/internal/repository"
+	"github.com/sku4/mslu-parser/internal/service/analyzer/duplicate"
+	"github.com/sku4/mslu-parser/internal/service/analyzer/entity"
+	"github.com/sku4/mslu-parser/internal/service/analyzer/headline"
+	"github.com/sku4/mslu-parser/internal/service/analyzer/language"
+	"github.com/sku4/mslu-parser/internal/service/analyzer/lexicon"
+	"github.com/sku4/mslu-parser/internal/service/analyzer/readability"
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/pkg/logger"
+	"sort"
+	"sync"
+)
+
+//go:generate mockgen -source=analyzer.go -destination=mocks/analyzer.go
+
+// analyzeBatch is the count of articles saved at once by AnalyzeAll
+const analyzeBatch = 10000
+
+type iAnalyzer interface {
+	Analyze(*models.Complex)
+}
+
+type Service struct {
+	repos     *repository.Repository
+	analyzers []iAnalyzer
+	duplicate *duplicate.Duplicate
+	loadOnce  *sync.Once
+}
+
+func NewService(repos *repository.Repository) *Service {
+	d := duplicate.New()
+	return &Service{
+		repos: repos,
+		analyzers: []iAnalyzer{
+			headline.New(),
+			readability.New(),
+			lexicon.New(),
+			language.New(),
+			entity.New(),
+			d,
+		},
+		duplicate: d,
+		loadOnce:  &sync.Once{},
+	}
+}
+
+// Analyze fills analysis of complex with every analyzer
+func (s *Service) Analyze(cx *models.Complex) {
+	s.loadOnce.Do(s.loadFingerprints)
+	for _, a := range s.analyzers {
+		a.Analyze(cx)
+	}
+}
+
+// AnalyzeAll reanalyzes stored articles and saves results
+func (s *Service) AnalyzeAll(ctx context.Context) error {
+	log := logger.Get()
+	complexes, err := s.repos.Excel.GetComplexes(ctx)
+	if err != nil {
+		return err
+	}
+
+	// fingerprints are rebuilt from scratch, earlier articles become cluster originals
+	s.loadOnce.Do(func() {})
+	s.duplicate.Reset()
+	sort.SliceStable(complexes, func(i, j int) bool {
+		a, b := complexes[i].Date, complexes[j].Date
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero() && b.IsZero()
+		}
+		return a.Before(b)
+	})
+
+	// articles are saved in batches, a workbook is written once per batch
+	for start := 0; start < len(complexes); start += analyzeBatch {
+		end := start + analyzeBatch
+		if end > len(complexes) {
+			end = len(complexes)
+		}
+		for i := start; i < end; i++ {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+			s.Analyze(&complexes[i])
+		}
+		if err = s.repos.Excel.SetComplexes(ctx, complexes[start:end]); err != nil {
+			return errors.Wrap(err, "Analyze all")
+		}
+		log.Infof("Analyzed %d of %d articles", end, len(complexes))
+	}
+
+	return nil
+}
+
+// loadFingerprints indexes stored fingerprints so new articles are clustered against the corpus
+func (s *Service) loadFingerprints() {
+	complexes, err := s.repos.Excel.GetComplexes(context.Background())
+	if err != nil {
+		logger.Get().Warnf("Load fingerprints error: %s", err.Error())
+		return
+	}
+	s.duplicate.Load(complexes)
+}
@@ -0,0 +1,211 @@
+package duplicate
+
+import (
+	"fmt"
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/pkg/tokenizer"
+	"hash/fnv"
+	"math/bits"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// shingle is the word count of hashed features, pairs keep word order so teasers made of
+	// the same function words do not collide while one edited word changes only two features
+	shingle = 2
+	// maxDistance is the largest hamming distance between fingerprints of near-duplicates
+	maxDistance = 6
+	// bands split fingerprints for lookup, fingerprints within maxDistance share at least one band
+	bands = maxDistance + 1
+)
+
+type entry struct {
+	simHash   uint64
+	cluster   string
+	duplicate bool
+}
+
+// Duplicate fingerprints articles and assigns clusters against the articles seen before
+type Duplicate struct {
+	mutex   sync.Mutex
+	entries map[string]entry
+	// index holds urls by value of each band of their fingerprint
+	index [bands]map[uint64][]string
+}
+
+func New() *Duplicate {
+	d := &Duplicate{}
+	d.reset()
+
+	return d
+}
+
+// Load indexes fingerprints stored with complexes
+func (d *Duplicate) Load(complexes []models.Complex) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for _, cx := range complexes {
+		fp := cx.Analysis.Fingerprint
+		if fp == nil {
+			continue
+		}
+		simHash, err := strconv.ParseUint(fp.SimHash, 16, 64)
+		if err != nil {
+			continue
+		}
+		d.put(cx.Url, entry{
+			simHash:   simHash,
+			cluster:   fp.Cluster,
+			duplicate: fp.Duplicate,
+		})
+	}
+}
+
+// Reset drops indexed fingerprints
+func (d *Duplicate) Reset() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.reset()
+}
+
+func (d *Duplicate) reset() {
+	d.entries = make(map[string]entry)
+	for i := range d.index {
+		d.index[i] = make(map[uint64][]string)
+	}
+}
+
+// put indexes entry of url replacing its previous fingerprint
+func (d *Duplicate) put(url string, e entry) {
+	if prev, ok := d.entries[url]; ok {
+		for i := range d.index {
+			key := band(prev.simHash, i)
+			urls := d.index[i][key]
+			for j, u := range urls {
+				if u == url {
+					urls = append(urls[:j], urls[j+1:]...)
+					break
+				}
+			}
+			if len(urls) == 0 {
+				delete(d.index[i], key)
+			} else {
+				d.index[i][key] = urls
+			}
+		}
+	}
+	d.entries[url] = e
+	for i := range d.index {
+		key := band(e.simHash, i)
+		d.index[i][key] = append(d.index[i][key], url)
+	}
+}
+
+// band returns bits of band i of fingerprint
+func band(simHash uint64, i int) uint64 {
+	start, end := i*64/bands, (i+1)*64/bands
+
+	return simHash >> start & (1<<(end-start) - 1)
+}
+
+// Analyze fingerprints complex, the article joins the cluster of its nearest indexed article
+// and is marked duplicate, otherwise it opens a new cluster named by its fingerprint
+func (d *Duplicate) Analyze(cx *models.Complex) {
+	text := []string{cx.Title, cx.OverTitle, cx.Lead}
+	text = append(text, cx.Subtitles...)
+	text = append(text, cx.ImageTitles...)
+	simHash, ok := SimHash(strings.Join(text, "\n"))
+	if !ok {
+		cx.Analysis.Fingerprint = nil
+		return
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	e := entry{
+		simHash: simHash,
+		cluster: fmt.Sprintf("%016x", simHash),
+	}
+	prev, hasPrev := d.entries[cx.Url]
+	nearest, hasNearest := d.nearest(simHash, cx.Url)
+	switch {
+	case hasPrev && (!hasNearest || nearest.cluster == prev.cluster):
+		// updated article keeps its place in the cluster
+		e.cluster, e.duplicate = prev.cluster, prev.duplicate
+	case hasNearest:
+		e.cluster, e.duplicate = nearest.cluster, true
+	}
+	d.put(cx.Url, e)
+
+	cx.Analysis.Fingerprint = &models.Fingerprint{
+		SimHash:   fmt.Sprintf("%016x", simHash),
+		Cluster:   e.cluster,
+		Duplicate: e.duplicate,
+	}
+}
+
+// nearest returns the closest indexed entry within maxDistance except the article itself,
+// only entries sharing a band with the fingerprint are compared
+func (d *Duplicate) nearest(simHash uint64, url string) (entry, bool) {
+	best, bestDistance := entry{}, maxDistance+1
+	for i := range d.index {
+		for _, u := range d.index[i][band(simHash, i)] {
+			if u == url {
+				continue
+			}
+			e := d.entries[u]
+			distance := bits.OnesCount64(simHash ^ e.simHash)
+			// ties go to the smaller cluster name to keep assignment stable across index order
+			if distance < bestDistance || distance == bestDistance && e.cluster < best.cluster {
+				best, bestDistance = e, distance
+			}
+		}
+	}
+
+	return best, bestDistance <= maxDistance
+}
+
+// SimHash returns 64-bit SimHash of lowercase word shingles of text, false for text without words
+func SimHash(text string) (uint64, bool) {
+	words := make([]string, 0)
+	for _, token := range tokenizer.Tokens(text) {
+		if token.Kind != tokenizer.Punct {
+			words = append(words, strings.ToLower(token.Form))
+		}
+	}
+	if len(words) == 0 {
+		return 0, false
+	}
+
+	var weights [64]int
+	size := shingle
+	if len(words) < size {
+		size = len(words)
+	}
+	for i := 0; i+size <= len(words); i++ {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(strings.Join(words[i:i+size], " ")))
+		feature := h.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if feature&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var simHash uint64
+	for bit := 0; bit < 64; bit++ {
+		if weights[bit] > 0 {
+			simHash |= 1 << bit
+		}
+	}
+
+	return simHash, true
+}
@@ -0,0 +1,87 @@
+package duplicate
+
+import (
+	"github.com/sku4/mslu-parser/models"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name      string
+		first     models.Complex
+		second    models.Complex
+		duplicate bool
+	}{
+		{
+			name: "edited agency text",
+			first: models.Complex{
+				ExcelUrl: models.ExcelUrl{Url: "https://example.com/a"},
+				Title:    "Bundestag beschließt Haushalt für das kommende Jahr",
+				Lead: "Nach wochenlangen Verhandlungen hat der Bundestag den Haushalt für das kommende Jahr " +
+					"beschlossen. Die Koalition setzte sich mit ihrer Mehrheit gegen die Stimmen der Opposition " +
+					"durch, die vor allem die geplanten Kürzungen bei der Entwicklungshilfe und beim Bürgergeld " +
+					"kritisiert hatte. Der Bundesrat muss dem Gesetz noch zustimmen.",
+			},
+			second: models.Complex{
+				ExcelUrl: models.ExcelUrl{Url: "https://example.com/b"},
+				Title:    "Bundestag beschließt Haushalt für das kommende Jahr",
+				Lead: "Nach wochenlangen Verhandlungen hat der Bundestag am Freitag den Haushalt für das kommende Jahr " +
+					"beschlossen. Die Koalition setzte sich mit ihrer Mehrheit gegen die Stimmen der Opposition " +
+					"durch, die vor allem die geplanten Kürzungen bei der Entwicklungshilfe und beim Bürgergeld " +
+					"kritisiert hatte. Der Bundesrat muss dem Gesetz noch zustimmen.",
+			},
+			duplicate: true,
+		},
+		{
+			name: "similar teasers of different stories",
+			first: models.Complex{
+				ExcelUrl: models.ExcelUrl{Url: "https://example.com/c"},
+				Title:    "Die Regierung hat sich auf einen neuen Haushalt geeinigt",
+				Lead:     "Das ist nach langen Verhandlungen nun doch noch gelungen. Der Bundestag muss noch zustimmen.",
+			},
+			second: models.Complex{
+				ExcelUrl: models.ExcelUrl{Url: "https://example.com/d"},
+				Title:    "Die Opposition hat sich gegen einen neuen Haushalt gestellt",
+				Lead:     "Das ist nach langen Beratungen nun doch noch geschehen. Der Bundesrat muss noch entscheiden.",
+			},
+			duplicate: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := New()
+			first, second := tt.first, tt.second
+			d.Analyze(&first)
+			d.Analyze(&second)
+			if first.Analysis.Fingerprint == nil || second.Analysis.Fingerprint == nil {
+				t.Fatal("Analyze() fingerprint = nil")
+			}
+			if first.Analysis.Fingerprint.Duplicate {
+				t.Error("first article duplicate = true, want false")
+			}
+			if got := second.Analysis.Fingerprint.Duplicate; got != tt.duplicate {
+				t.Errorf("second article duplicate = %v, want %v", got, tt.duplicate)
+			}
+			sameCluster := first.Analysis.Fingerprint.Cluster == second.Analysis.Fingerprint.Cluster
+			if sameCluster != tt.duplicate {
+				t.Errorf("same cluster = %v, want %v", sameCluster, tt.duplicate)
+			}
+		})
+	}
+}
+
+func TestAnalyzeUpdated(t *testing.T) {
+	d := New()
+	cx := models.Complex{ExcelUrl: models.ExcelUrl{Url: "https://example.com/a"}, Title: "Bundestag beschließt Haushalt für das kommende Jahr"}
+	d.Analyze(&cx)
+	// the updated article leaves the band buckets of its previous fingerprint
+	cx.Title = "Schwere Unwetter verwüsten Teile von Süddeutschland"
+	d.Analyze(&cx)
+
+	other := models.Complex{ExcelUrl: models.ExcelUrl{Url: "https://example.com/b"}, Title: "Bundestag beschließt Haushalt für das kommende Jahr"}
+	d.Analyze(&other)
+	if other.Analysis.Fingerprint.Duplicate {
+		t.Error("duplicate of replaced fingerprint = true, want false")
+	}
+}
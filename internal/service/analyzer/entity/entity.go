@@ -0,0 +1,376 @@
+package entity
+
+import (
+	_ "embed"
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/pkg/tokenizer"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	//go:embed data/persons.txt
+	personsData string
+	//go:embed data/places.txt
+	placesData string
+	//go:embed data/organizations.txt
+	organizationsData string
+	//go:embed data/parties.txt
+	partiesData string
+	//go:embed data/firstnames.txt
+	firstNamesData string
+
+	// titles precede person names, compounds like "CDU-Chef" end with them
+	titles = toSet(`Kanzler Kanzlerin Bundeskanzler Bundeskanzlerin Minister Ministerin Ministerpräsident
+		Ministerpräsidentin Präsident Präsidentin Bundespräsident Bürgermeister Bürgermeisterin Chef Chefin
+		Vorsitzende Vorsitzender Parteichef Parteichefin Fraktionschef Fraktionschefin Politiker Politikerin
+		Abgeordnete Abgeordneter Sprecher Sprecherin Kandidat Kandidatin Herr Frau Trainer Papst König Königin`)
+	// particles inside person names like "von der Leyen"
+	particles = toSet(`von van de der zu den`)
+	// prepositions preceding places in the place suffix rule
+	placePrepositions = toSet(`in aus nach bei`)
+	placeSuffixes     = []string{"burg", "berg", "dorf", "stadt", "hausen", "heim", "ingen", "feld", "hagen", "bach"}
+	// suffixes of single-token organizations like "Bundesgesundheitsministerium"
+	organizationSuffixes = []string{"ministerium", "gericht", "behörde", "verband", "gewerkschaft", "konzern"}
+	legalForms           = toSet(`AG GmbH SE KG KGaA e.V. Inc. Ltd.`)
+	// suffixes of common nouns which are not names, "Frau Polizei"
+	nounSuffixes = []string{"ung", "heit", "keit", "schaft", "ei", "tion", "tät", "ismus", "nis", "tum", "ment",
+		"ling", "chen"}
+	// capitalized function words which do not start names
+	stopwords = toSet(`Der Die Das Den Dem Des Ein Eine Einen Einem Einer Eines Und Oder Aber Doch Im In Am An
+		Auf Aus Bei Mit Nach Von Vor Zu Zum Zur Für Über Unter Wie Was Wer Wo Warum Wenn Als Auch Nur Noch
+		Schon Jetzt Hier Dort Dann Nun So Es Er Sie Wir Ihr Ich Man Kein Keine Nicht`)
+)
+
+// span is an entity found at token positions [start, end) of a value
+type span struct {
+	start, end int
+	// part limits the entity to the first hyphen part of the start token, e.g. "CDU" of "CDU-Chef"
+	part string
+	typ  string
+	name string
+}
+
+type Entity struct {
+	gazetteer  map[string]gazetteerEntry
+	maxTokens  int
+	firstNames map[string]struct{}
+}
+
+type gazetteerEntry struct {
+	typ  string
+	name string
+	// adjective aliases like "Die Linke" are not matched before a capitalized word, "Die Linke Hand"
+	adjective bool
+}
+
+func New() *Entity {
+	e := &Entity{
+		gazetteer:  make(map[string]gazetteerEntry),
+		firstNames: toSet(firstNamesData),
+	}
+	// earlier types win when aliases collide
+	e.load(partiesData, models.EntityParty)
+	e.load(organizationsData, models.EntityOrganization)
+	e.load(personsData, models.EntityPerson)
+	e.load(placesData, models.EntityPlace)
+
+	return e
+}
+
+func (e *Entity) load(data, typ string) {
+	for _, line := range strings.Split(data, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		aliases := strings.Split(line, "|")
+		canonical := strings.TrimPrefix(aliases[0], "~")
+		for _, alias := range aliases {
+			adjective := strings.HasPrefix(alias, "~")
+			tokens := tokenizer.Tokens(strings.TrimPrefix(alias, "~"))
+			key := key(tokens)
+			if _, ok := e.gazetteer[key]; ok {
+				continue
+			}
+			e.gazetteer[key] = gazetteerEntry{typ: typ, name: canonical, adjective: adjective}
+			if len(tokens) > e.maxTokens {
+				e.maxTokens = len(tokens)
+			}
+		}
+	}
+}
+
+func (e *Entity) Analyze(cx *models.Complex) {
+	entities := make([]models.Entity, 0)
+	for _, field := range models.TextFields {
+		for i, value := range cx.Values(field) {
+			for _, found := range e.Find(value) {
+				found.Field = field
+				if field.IsList() {
+					found.Item = i
+				}
+				entities = append(entities, found)
+			}
+		}
+	}
+	cx.Analysis.Entities = entities
+}
+
+// Find returns entities of text with rune offsets, gazetteer matches take precedence over rules
+func (e *Entity) Find(text string) []models.Entity {
+	tokens := tokenizer.Tokens(text)
+	covered := make([]bool, len(tokens))
+	spans := make([]span, 0)
+	add := func(s span) {
+		for i := s.start; i < s.end; i++ {
+			if covered[i] {
+				return
+			}
+		}
+		for i := s.start; i < s.end; i++ {
+			covered[i] = true
+		}
+		spans = append(spans, s)
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		if s, ok := e.match(tokens, i); ok {
+			add(s)
+			i = s.end - 1
+		}
+	}
+	// rules may start at covered tokens, "CDU-Chef Friedrich Merz" tags the party and the person
+	for i := range tokens {
+		for _, rule := range []func([]tokenizer.Token, int) (span, bool){e.titleRule, e.firstNameRule, placeRule,
+			organizationRule} {
+			if s, ok := rule(tokens, i); ok {
+				add(s)
+				break
+			}
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	return entities(text, tokens, spans)
+}
+
+// match returns the longest gazetteer alias starting at token i, a genitive "s" and the
+// first part of hyphen compounds like "SPD-Fraktion" are matched as well
+func (e *Entity) match(tokens []tokenizer.Token, i int) (span, bool) {
+	for n := e.maxTokens; n > 0; n-- {
+		if i+n > len(tokens) {
+			continue
+		}
+		k := key(tokens[i : i+n])
+		if entry, ok := e.gazetteer[k]; ok && !(entry.adjective && beforeNoun(tokens, i+n)) {
+			return span{start: i, end: i + n, typ: entry.typ, name: entry.name}, true
+		}
+		if n != 1 {
+			continue
+		}
+		if entry, ok := e.gazetteer[strings.TrimSuffix(k, "s")]; ok && k != strings.TrimSuffix(k, "s") &&
+			(entry.typ == models.EntityPerson || entry.typ == models.EntityPlace) {
+			return span{start: i, end: i + 1, typ: entry.typ, name: entry.name}, true
+		}
+		if part, _, ok := strings.Cut(k, "-"); ok {
+			if entry, ok := e.gazetteer[part]; ok {
+				return span{start: i, end: i + 1, part: part, typ: entry.typ, name: entry.name}, true
+			}
+		}
+	}
+
+	return span{}, false
+}
+
+// beforeNoun reports whether token i is a capitalized word other than a function word
+func beforeNoun(tokens []tokenizer.Token, i int) bool {
+	if i >= len(tokens) || tokens[i].Kind != tokenizer.Word || !isCapitalized(tokens[i].Form) {
+		return false
+	}
+	_, ok := stopwords[tokens[i].Form]
+
+	return !ok
+}
+
+// titleRule tags up to two capitalized name-like words after a title as person, "Kanzler Olaf Scholz"
+func (e *Entity) titleRule(tokens []tokenizer.Token, i int) (span, bool) {
+	if !isTitle(tokens[i].Form) {
+		return span{}, false
+	}
+	end := name(tokens, i+1, 2)
+	for j := i + 1; j < end; j++ {
+		if isNoun(tokens[j].Form) {
+			end = j
+			break
+		}
+	}
+	if end == i+1 {
+		return span{}, false
+	}
+
+	return span{start: i + 1, end: end, typ: models.EntityPerson}, true
+}
+
+// firstNameRule tags a known first name followed by capitalized words as person
+func (e *Entity) firstNameRule(tokens []tokenizer.Token, i int) (span, bool) {
+	if _, ok := e.firstNames[tokens[i].Form]; !ok {
+		return span{}, false
+	}
+	end := name(tokens, i, 3)
+	if end <= i+1 {
+		return span{}, false
+	}
+
+	return span{start: i, end: end, typ: models.EntityPerson}, true
+}
+
+// placeRule tags a capitalized word with a town suffix after a preposition, "in Oldenburg"
+func placeRule(tokens []tokenizer.Token, i int) (span, bool) {
+	if i == 0 || !isCapitalized(tokens[i].Form) {
+		return span{}, false
+	}
+	if _, ok := placePrepositions[strings.ToLower(tokens[i-1].Form)]; !ok {
+		return span{}, false
+	}
+	for _, suffix := range placeSuffixes {
+		if strings.HasSuffix(tokens[i].Form, suffix) && len(tokens[i].Form) > len(suffix)+2 {
+			return span{start: i, end: i + 1, typ: models.EntityPlace}, true
+		}
+	}
+
+	return span{}, false
+}
+
+// organizationRule tags capitalized words before a legal form and institution compounds
+func organizationRule(tokens []tokenizer.Token, i int) (span, bool) {
+	if !isCapitalized(tokens[i].Form) {
+		return span{}, false
+	}
+	if _, ok := stopwords[tokens[i].Form]; ok {
+		return span{}, false
+	}
+	for _, suffix := range organizationSuffixes {
+		form := strings.ToLower(tokens[i].Form)
+		if strings.HasSuffix(form, suffix) && utf8.RuneCountInString(form) > utf8.RuneCountInString(suffix)+3 {
+			return span{start: i, end: i + 1, typ: models.EntityOrganization}, true
+		}
+	}
+	end := i
+	for end < len(tokens) && end-i < 3 && isCapitalized(tokens[end].Form) {
+		if _, ok := legalForms[tokens[end].Form]; ok {
+			break
+		}
+		end++
+	}
+	if end > i && end < len(tokens) {
+		if _, ok := legalForms[tokens[end].Form]; ok {
+			return span{start: i, end: end + 1, typ: models.EntityOrganization}, true
+		}
+	}
+
+	return span{}, false
+}
+
+// name returns end of up to max capitalized words starting at token i, particles are skipped inside
+func name(tokens []tokenizer.Token, i, max int) int {
+	end, words := i, 0
+	for j := i; j < len(tokens) && words < max; j++ {
+		form := tokens[j].Form
+		if _, ok := particles[form]; ok && j > i {
+			continue
+		}
+		if !isCapitalized(form) || isTitle(form) {
+			break
+		}
+		if _, ok := stopwords[form]; ok {
+			break
+		}
+		words++
+		end = j + 1
+	}
+
+	return end
+}
+
+// isNoun reports words with suffixes of common nouns, "Polizei" after "Frau" is no name
+func isNoun(form string) bool {
+	lower := strings.ToLower(form)
+	for _, suffix := range nounSuffixes {
+		if strings.HasSuffix(lower, suffix) && utf8.RuneCountInString(lower) > utf8.RuneCountInString(suffix)+3 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isTitle(form string) bool {
+	if _, ok := titles[form]; ok {
+		return true
+	}
+	if _, last, ok := strings.Cut(form, "-"); ok {
+		_, ok = titles[last]
+		return ok
+	}
+
+	return false
+}
+
+func isCapitalized(form string) bool {
+	r, _ := utf8.DecodeRuneInString(form)
+
+	return unicode.IsUpper(r)
+}
+
+// entities converts token spans to entities with rune offsets in text
+func entities(text string, tokens []tokenizer.Token, spans []span) []models.Entity {
+	runes := []rune(text)
+	result := make([]models.Entity, 0, len(spans))
+	for _, s := range spans {
+		start := tokens[s.start].Start
+		end := tokens[s.end-1].Start + utf8.RuneCountInString(tokens[s.end-1].Form)
+		if s.part != "" {
+			end = start + utf8.RuneCountInString(s.part)
+		}
+		surface := string(runes[start:end])
+		name := s.name
+		if name == "" {
+			name = surface
+		}
+		result = append(result, models.Entity{
+			Type:  s.typ,
+			Name:  name,
+			Text:  surface,
+			Start: start,
+			End:   end,
+		})
+	}
+
+	return result
+}
+
+func key(tokens []tokenizer.Token) string {
+	forms := make([]string, len(tokens))
+	for i, token := range tokens {
+		forms[i] = token.Form
+	}
+
+	return strings.Join(forms, " ")
+}
+
+func toSet(s string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, line := range strings.Split(s, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		for _, word := range strings.Fields(line) {
+			set[word] = struct{}{}
+		}
+	}
+
+	return set
+}
@@ -0,0 +1,92 @@
+package entity
+
+import (
+	"github.com/sku4/mslu-parser/models"
+	"reflect"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []models.Entity
+	}{
+		{"gazetteer person and place", "Olaf Scholz reist nach Paris",
+			[]models.Entity{
+				{Type: models.EntityPerson, Name: "Olaf Scholz", Text: "Olaf Scholz", Start: 0, End: 11},
+				{Type: models.EntityPlace, Name: "Paris", Text: "Paris", Start: 23, End: 28},
+			}},
+		{"alias to canonical name", "Merz kritisiert die Grünen",
+			[]models.Entity{
+				{Type: models.EntityPerson, Name: "Friedrich Merz", Text: "Merz", Start: 0, End: 4},
+				{Type: models.EntityParty, Name: "Grüne", Text: "Grünen", Start: 20, End: 26},
+			}},
+		{"party of hyphen compound", "SPD-Fraktion stimmt zu",
+			[]models.Entity{{Type: models.EntityParty, Name: "SPD", Text: "SPD", Start: 0, End: 3}}},
+		{"genitive", "Merkels Erbe",
+			[]models.Entity{{Type: models.EntityPerson, Name: "Angela Merkel", Text: "Merkels", Start: 0, End: 7}}},
+		{"title rule", "Ministerin Klara Geywitz und Frau Müller",
+			[]models.Entity{
+				{Type: models.EntityPerson, Name: "Klara Geywitz", Text: "Klara Geywitz", Start: 11, End: 24},
+				{Type: models.EntityPerson, Name: "Müller", Text: "Müller", Start: 34, End: 40},
+			}},
+		{"first name rule", "Laura Schneider gewinnt",
+			[]models.Entity{{Type: models.EntityPerson, Name: "Laura Schneider", Text: "Laura Schneider", Start: 0, End: 15}}},
+		{"place suffix rule", "Unfall in Oldenburg",
+			[]models.Entity{{Type: models.EntityPlace, Name: "Oldenburg", Text: "Oldenburg", Start: 10, End: 19}}},
+		{"organization suffix rule", "Das Bundesgesundheitsministerium warnt",
+			[]models.Entity{{Type: models.EntityOrganization, Name: "Bundesgesundheitsministerium",
+				Text: "Bundesgesundheitsministerium", Start: 4, End: 32}}},
+		{"legal form rule", "Die Muster Werke AG wächst",
+			[]models.Entity{{Type: models.EntityOrganization, Name: "Muster Werke AG", Text: "Muster Werke AG", Start: 4, End: 19}}},
+		{"party before verb", "Die Linke verliert",
+			[]models.Entity{{Type: models.EntityParty, Name: "Die Linke", Text: "Die Linke", Start: 0, End: 9}}},
+		{"adjective before noun", "Die Linke Hand", nil},
+		{"common noun bahn", "Er fährt mit der Bahn", nil},
+		{"common noun allianz", "Allianz der Willigen", nil},
+		{"common noun union", "Die Union der Länder", nil},
+		{"title before common noun", "Frau Polizei", nil},
+		{"rune offsets after umlauts", "Über Köln",
+			[]models.Entity{{Type: models.EntityPlace, Name: "Köln", Text: "Köln", Start: 5, End: 9}}},
+		{"offsets after spaces and quotes", "„Nein“,  sagte  Habeck",
+			[]models.Entity{{Type: models.EntityPerson, Name: "Robert Habeck", Text: "Habeck", Start: 16, End: 22}}},
+	}
+
+	e := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := e.Find(tt.text)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Find(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	cx := models.Complex{
+		Title:     "Scholz in Berlin",
+		Subtitles: []string{"Ohne Namen", "Kritik von Baerbock"},
+	}
+	New().Analyze(&cx)
+
+	want := []models.Entity{
+		{Field: models.FieldTitle, Type: models.EntityPerson, Name: "Olaf Scholz", Text: "Scholz", Start: 0, End: 6},
+		{Field: models.FieldTitle, Type: models.EntityPlace, Name: "Berlin", Text: "Berlin", Start: 10, End: 16},
+		{Field: models.FieldSubtitles, Item: 1, Type: models.EntityPerson, Name: "Annalena Baerbock", Text: "Baerbock",
+			Start: 11, End: 19},
+	}
+	if !reflect.DeepEqual(cx.Analysis.Entities, want) {
+		t.Errorf("Analyze() entities = %+v, want %+v", cx.Analysis.Entities, want)
+	}
+
+	empty := models.Complex{Title: "Nichts los"}
+	New().Analyze(&empty)
+	if empty.Analysis.Entities == nil || len(empty.Analysis.Entities) != 0 {
+		t.Errorf("Analyze() entities = %#v, want empty non-nil", empty.Analysis.Entities)
+	}
+}
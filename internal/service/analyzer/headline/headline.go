@@ -0,0 +1,136 @@
+package headline
+
+import (
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/pkg/tokenizer"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type Headline struct {
+}
+
+func New() *Headline {
+	return &Headline{}
+}
+
+func (h *Headline) Analyze(cx *models.Complex) {
+	headline := Classify(cx.Title, cx.OverTitle)
+	cx.Analysis.Headline = &headline
+}
+
+var (
+	// closedClass lists lowercase function words and adverbs that look like verb forms
+	closedClass = set(`aber als am an auch auf aus bei bereits bis da damit dann das dass dem den denen der des
+		dessen die dies diese diesem diesen dieser dieses doch dort du durch ein eine einem einen einer eines
+		er erst es etwa etwas euch fast für gegen gut halt heute ich ihm ihn ihnen ihr ihre ihrem ihren ihrer im
+		in ins jetzt man mehr meist mit nach neben nicht noch nun nur ob oder oft ohne seit selbst sich sie so
+		statt trotz um und uns unser unten unter vom von vor weil weit wen wenn wer wie wieder wir zuletzt zum
+		zur zwischen über unten oben längst sonst insgesamt zunächst bislang letzt nächst
+		einen deren denen allen alten anderen ganzen großen guten hohen jungen kleinen letzten meisten
+		neuen ersten zweiten eigenen beiden vielen wenigen weiteren wichtigsten besten`)
+	// auxiliaries lists frequent finite verb forms of auxiliaries and modals
+	auxiliaries = set(`ist sind war waren bin bist seid wird werden wurde wurden würde würden hat haben hatte
+		hatten habe kann können konnte konnten muss müssen musste mussten soll sollen sollte sollten will wollen
+		wollte wollten darf dürfen durfte gibt geht kommt bleibt steht liegt sagt macht lässt sieht droht fehlt`)
+	// imperatives lists informal imperative forms used at headline start
+	imperatives = set(`lies schau hör mach lass geh komm nimm gib hilf sieh vergiss denk stell hört seht lest
+		macht lasst kommt schaut stoppt rettet wählt helft`)
+	numberWords = set(`null eins zwei drei vier fünf sechs sieben acht neun zehn elf zwölf zwanzig dreißig
+		hundert tausend million millionen milliarde milliarden halb hälfte doppelt`)
+)
+
+func set(words string) map[string]struct{} {
+	m := make(map[string]struct{})
+	for _, w := range strings.Fields(words) {
+		m[w] = struct{}{}
+	}
+
+	return m
+}
+
+// Classify labels structural features of headline, verb detection is a heuristic without a tagger
+func Classify(title, overTitle string) models.Headline {
+	h := models.Headline{
+		Question:       strings.ContainsRune(title, '?'),
+		Exclamation:    strings.ContainsRune(title, '!'),
+		Quote:          strings.ContainsAny(title, "„“”\"»«‚‘"),
+		Colon:          strings.ContainsRune(title, ':'),
+		Ellipsis:       strings.Contains(title, "...") || strings.ContainsRune(title, '…'),
+		Chars:          utf8.RuneCountInString(title),
+		OverTitleChars: utf8.RuneCountInString(overTitle),
+	}
+
+	tokens := tokenizer.Tokens(title)
+	words := make([]tokenizer.Token, 0, len(tokens))
+	for _, t := range tokens {
+		if t.Kind == tokenizer.Punct {
+			continue
+		}
+		words = append(words, t)
+		if t.Kind == tokenizer.Number {
+			h.Number = true
+		} else if _, ok := numberWords[strings.ToLower(t.Form)]; ok {
+			h.Number = true
+		}
+	}
+	h.Words = len(words)
+	for _, t := range tokenizer.Tokens(overTitle) {
+		if t.Kind != tokenizer.Punct {
+			h.OverTitleWords++
+		}
+	}
+
+	h.Imperative = !h.Question && isImperative(words, h.Exclamation)
+	h.Verbless = h.Words > 0 && !h.Imperative && !hasVerb(words)
+
+	return h
+}
+
+func isImperative(words []tokenizer.Token, exclamation bool) bool {
+	if len(words) == 0 {
+		return false
+	}
+	first := strings.ToLower(words[0].Form)
+	// formal imperative "Sparen Sie ..."
+	if len(words) > 1 && words[1].Form == "Sie" && strings.HasSuffix(first, "en") {
+		return true
+	}
+	if _, ok := imperatives[first]; ok {
+		return true
+	}
+
+	// informal plural imperative "Stoppt den Krieg!"
+	return exclamation && len(words) > 1 && strings.HasSuffix(first, "t") && !isCapitalized(words[1])
+}
+
+// hasVerb looks for auxiliaries or lowercase words with finite verb endings,
+// capitalized words are skipped as nouns or sentence starts
+func hasVerb(words []tokenizer.Token) bool {
+	for _, w := range words {
+		if w.Kind != tokenizer.Word {
+			continue
+		}
+		form := strings.ToLower(w.Form)
+		if _, ok := auxiliaries[form]; ok {
+			return true
+		}
+		if _, ok := closedClass[form]; ok || isCapitalized(w) {
+			continue
+		}
+		for _, suffix := range []string{"t", "en", "te", "ten", "st", "ern", "eln"} {
+			if strings.HasSuffix(form, suffix) && len([]rune(form)) > 3 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isCapitalized(w tokenizer.Token) bool {
+	r, _ := utf8.DecodeRuneInString(w.Form)
+
+	return unicode.IsUpper(r)
+}
@@ -0,0 +1,53 @@
+package headline
+
+import (
+	"github.com/sku4/mslu-parser/models"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name      string
+		title     string
+		overTitle string
+		want      models.Headline
+	}{
+		{"question", "Wer zahlt für die Energiewende?", "",
+			models.Headline{Question: true, Words: 5, Chars: 31}},
+		{"exclamation", "Das ist ein Skandal!", "",
+			models.Headline{Exclamation: true, Words: 4, Chars: 20}},
+		{"quote", "„Wir schaffen das“", "",
+			models.Headline{Quote: true, Words: 3, Chars: 18}},
+		{"colon", "Bahnstreik: Was Reisende jetzt wissen müssen", "",
+			models.Headline{Colon: true, Words: 6, Chars: 44}},
+		{"verbless", "Chaos im Bundestag", "Haushalt",
+			models.Headline{Verbless: true, Words: 3, Chars: 18, OverTitleWords: 1, OverTitleChars: 8}},
+		{"finite verb", "Scholz verteidigt die Zeitenwende", "",
+			models.Headline{Words: 4, Chars: 33}},
+		{"auxiliary", "Die Inflation ist zurück", "",
+			models.Headline{Words: 4, Chars: 24}},
+		{"formal imperative", "Sparen Sie beim Heizen", "",
+			models.Headline{Imperative: true, Words: 4, Chars: 22}},
+		{"informal imperative", "Lies das, bevor du wählst", "",
+			models.Headline{Imperative: true, Words: 5, Chars: 25}},
+		{"plural imperative", "Stoppt den Krieg!", "",
+			models.Headline{Imperative: true, Exclamation: true, Words: 3, Chars: 17}},
+		{"digits", "3 Gründe für den Streik", "",
+			models.Headline{Number: true, Verbless: true, Words: 5, Chars: 23}},
+		{"number word", "Zwei Millionen Menschen fliehen", "",
+			models.Headline{Number: true, Words: 4, Chars: 31}},
+		{"ellipsis", "Und dann Corona...", "",
+			models.Headline{Ellipsis: true, Verbless: true, Words: 3, Chars: 18}},
+		{"ellipsis character", "Und dann…", "",
+			models.Headline{Ellipsis: true, Verbless: true, Words: 2, Chars: 9}},
+		{"empty", "", "", models.Headline{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.title, tt.overTitle); got != tt.want {
+				t.Errorf("Classify(%q) = %+v, want %+v", tt.title, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package language
+
+import (
+	"embed"
+	"github.com/sku4/mslu-parser/models"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	German = "de"
+
+	// maxGram is the longest character n-gram of profiles
+	maxGram = 3
+	// minLetters is the letter count below which text language is undetermined
+	minLetters = 12
+	// germanMargin is the mean log-likelihood per n-gram another language needs over german
+	germanMargin = 0.1
+	// shortGrams is the n-gram count below which the margin grows, profiles are trained on
+	// samples of about 1KB and titles full of names score close to other languages
+	shortGrams = 300
+)
+
+var (
+	//go:embed data/*.txt
+	samples embed.FS
+
+	// scripts identifying language without profiles, latin and cyrillic are resolved by profiles
+	scripts = []struct {
+		table *unicode.RangeTable
+		lang  string
+	}{
+		{unicode.Greek, "el"},
+		{unicode.Arabic, "ar"},
+		{unicode.Hebrew, "he"},
+		{unicode.Hangul, "ko"},
+		{unicode.Hiragana, "ja"},
+		{unicode.Katakana, "ja"},
+		{unicode.Han, "zh"},
+	}
+)
+
+type profile struct {
+	lang  string
+	grams map[string]float64
+	total float64
+}
+
+type Language struct {
+	latin    []*profile
+	cyrillic []*profile
+}
+
+func New() *Language {
+	l := &Language{}
+	entries, _ := samples.ReadDir("data")
+	for _, entry := range entries {
+		data, err := samples.ReadFile(path.Join("data", entry.Name()))
+		if err != nil {
+			continue
+		}
+		p := &profile{
+			lang:  strings.TrimSuffix(entry.Name(), ".txt"),
+			grams: make(map[string]float64),
+		}
+		for _, gram := range grams(string(data)) {
+			p.grams[gram]++
+			p.total++
+		}
+		if isCyrillic(string(data)) {
+			l.cyrillic = append(l.cyrillic, p)
+		} else {
+			l.latin = append(l.latin, p)
+		}
+	}
+	sort.Slice(l.latin, func(i, j int) bool { return l.latin[i].lang < l.latin[j].lang })
+	sort.Slice(l.cyrillic, func(i, j int) bool { return l.cyrillic[i].lang < l.cyrillic[j].lang })
+
+	return l
+}
+
+func (l *Language) Analyze(cx *models.Complex) {
+	lang := &models.Language{
+		Title:     l.Identify(cx.Title),
+		OverTitle: l.Identify(cx.OverTitle),
+		Lead:      l.Identify(cx.Lead),
+	}
+	for _, subtitle := range cx.Subtitles {
+		lang.Subtitles = append(lang.Subtitles, l.Identify(subtitle))
+	}
+	for _, imageTitle := range cx.ImageTitles {
+		lang.ImageTitles = append(lang.ImageTitles, l.Identify(imageTitle))
+	}
+
+	text := []string{cx.Title, cx.OverTitle, cx.Lead}
+	text = append(text, cx.Subtitles...)
+	text = append(text, cx.ImageTitles...)
+	lang.Article = l.Identify(strings.Join(text, "\n"))
+
+	cx.Analysis.Language = lang
+}
+
+// Identify returns ISO 639-1 code of text language, empty string when undetermined
+func (l *Language) Identify(text string) string {
+	letters, latin, cyrillic := 0, 0, 0
+	other := make(map[string]int)
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		default:
+			for _, s := range scripts {
+				if unicode.Is(s.table, r) {
+					other[s.lang]++
+					break
+				}
+			}
+		}
+	}
+
+	// dominant script decides candidates, japanese text mixes kana with han
+	lang, count := "", 0
+	for _, s := range scripts {
+		if other[s.lang] > count {
+			lang, count = s.lang, other[s.lang]
+		}
+	}
+	if lang == "zh" && other["ja"] > 0 {
+		lang = "ja"
+	}
+	if count > latin && count > cyrillic {
+		return lang
+	}
+	if letters < minLetters {
+		return ""
+	}
+	if cyrillic > latin {
+		return classify(l.cyrillic, text)
+	}
+
+	return classify(l.latin, text)
+}
+
+// classify returns language of profile with the highest naive Bayes score of text n-grams
+func classify(profiles []*profile, text string) string {
+	textGrams := grams(text)
+	best, bestScore, germanScore := "", math.Inf(-1), math.Inf(-1)
+	for _, p := range profiles {
+		score := 0.
+		for _, gram := range textGrams {
+			// add-one smoothing over profile size keeps unseen n-grams from zeroing the score
+			score += math.Log((p.grams[gram] + 1) / (p.total + float64(len(p.grams))))
+		}
+		if p.lang == German {
+			germanScore = score
+		}
+		if score > bestScore {
+			best, bestScore = p.lang, score
+		}
+	}
+	// corpus is german, another language has to win by margin growing on short texts
+	margin := germanMargin
+	if len(textGrams) < shortGrams {
+		margin = germanMargin * shortGrams / float64(len(textGrams))
+	}
+	if (bestScore-germanScore)/float64(len(textGrams)) < margin {
+		return German
+	}
+
+	return best
+}
+
+// grams returns lowercase character n-grams up to maxGram of every word padded with spaces
+func grams(text string) []string {
+	var result []string
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, word := range words {
+		runes := []rune(" " + word + " ")
+		for n := 1; n <= maxGram; n++ {
+			for i := 0; i+n <= len(runes); i++ {
+				if n == 1 && runes[i] == ' ' {
+					continue
+				}
+				result = append(result, string(runes[i:i+n]))
+			}
+		}
+	}
+
+	return result
+}
+
+func isCyrillic(text string) bool {
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			return unicode.Is(unicode.Cyrillic, r)
+		}
+	}
+
+	return false
+}
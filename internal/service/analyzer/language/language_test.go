@@ -0,0 +1,55 @@
+package language
+
+import "testing"
+
+func TestIdentify(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"german lead", "Die Bundesregierung will die Schuldenbremse im kommenden Jahr erneut aussetzen. " +
+			"Finanzminister Lindner lehnt das ab, die Grünen drängen auf eine Reform.", "de"},
+		{"german lead with anglicisms", "Das Start-up aus Berlin hat in einer neuen Finanzierungsrunde " +
+			"50 Millionen Euro eingesammelt. Der CEO spricht von einem Meilenstein für das Team.", "de"},
+		{"german title", "Scholz verteidigt die Zeitenwende", "de"},
+		{"german title of names", "Merkel trifft Macron in Paris", "de"},
+		{"german overtitle", "Nahost-Konflikt", "de"},
+		{"german title of foreign names", "Biden in Berlin", "de"},
+		{"german title with quote", "Trump droht China mit neuen Zöllen", "de"},
+		{"german title with english name", "Champions League: Bayern verliert", "de"},
+		{"german lead of names", "Emmanuel Macron und Giorgia Meloni haben in Rom über Migration beraten. " +
+			"Auch Ursula von der Leyen war dabei.", "de"},
+		{"english lead", "The German government is facing growing pressure to send more weapons to Ukraine. " +
+			"Chancellor Olaf Scholz has so far resisted calls from his coalition partners.", "en"},
+		{"english title", "Why Germany's Economy Is Struggling", "en"},
+		{"english title of names", "Putin says peace talks are possible", "en"},
+		{"french lead", "Le gouvernement allemand a présenté mercredi son projet de budget pour l'année prochaine. " +
+			"L'opposition dénonce des coupes dans les dépenses sociales.", "fr"},
+		{"spanish lead", "El gobierno alemán presentó el miércoles su proyecto de presupuesto para el próximo año. " +
+			"La oposición critica los recortes en el gasto social.", "es"},
+		{"italian lead", "Il governo tedesco ha presentato mercoledì il progetto di bilancio per il prossimo anno. " +
+			"L'opposizione critica i tagli alla spesa sociale.", "it"},
+		{"dutch lead", "De Duitse regering heeft woensdag haar begroting voor volgend jaar gepresenteerd. " +
+			"De oppositie bekritiseert de bezuinigingen op sociale uitgaven.", "nl"},
+		{"polish lead", "Niemiecki rząd przedstawił w środę projekt budżetu na przyszły rok. " +
+			"Opozycja krytykuje cięcia wydatków socjalnych.", "pl"},
+		{"turkish lead", "Alman hükümeti çarşamba günü gelecek yılın bütçe taslağını sundu. " +
+			"Muhalefet sosyal harcamalardaki kesintileri eleştiriyor.", "tr"},
+		{"russian lead", "Правительство Германии в среду представило проект бюджета на следующий год. " +
+			"Оппозиция критикует сокращение социальных расходов.", "ru"},
+		{"ukrainian lead", "Уряд Німеччини в середу представив проєкт бюджету на наступний рік. " +
+			"Опозиція критикує скорочення соціальних видатків.", "uk"},
+		{"greek script", "Η γερμανική κυβέρνηση", "el"},
+		{"too short", "Ja!", ""},
+	}
+
+	l := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := l.Identify(tt.text); got != tt.want {
+				t.Errorf("Identify(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
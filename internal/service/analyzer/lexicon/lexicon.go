@@ -0,0 +1,234 @@
+package lexicon
+
+import (
+	_ "embed"
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/pkg/tokenizer"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	minPart     = 3
+	maxParts    = 4
+	minCompound = 7
+	// minAffix is the least rune count of listed anglicisms matched as compound parts, shorter
+	// ones like "fan" and "team" match whole words, hyphen parts and listed compounds only
+	minAffix = 5
+)
+
+var (
+	//go:embed data/lexicon.txt
+	lexiconData string
+	//go:embed data/anglicisms.txt
+	anglicismsData string
+
+	// linking elements between compound parts, longest first
+	linkers = []string{"es", "en", "er", "s", "n", "e"}
+	// inflection endings stripped from the last compound part
+	endings = []string{"innen", "in", "en", "er", "es", "e", "n", "s"}
+
+	englishPatterns = []struct {
+		name string
+		re   *regexp.Regexp
+	}{
+		{"sh", regexp.MustCompile(`(^|[^c])sh`)},
+		{"ow", regexp.MustCompile(`ow($|[^aeiouäöü])`)},
+		{"ea", regexp.MustCompile(`ea`)},
+		{"oo", regexp.MustCompile(`oo[^mrt]`)},
+		{"ing", regexp.MustCompile(`[^l]ing(s)?$`)},
+		{"ay", regexp.MustCompile(`ay`)},
+		{"c", regexp.MustCompile(`(^|[aeiou])c[aou]`)},
+		{"y", regexp.MustCompile(`[^aeo]y[^aeiou]`)},
+	}
+)
+
+type Lexicon struct {
+	freq       map[string]float64
+	anglicisms map[string]struct{}
+}
+
+func New() *Lexicon {
+	l := &Lexicon{
+		freq:       make(map[string]float64),
+		anglicisms: make(map[string]struct{}),
+	}
+	for _, line := range strings.Split(lexiconData, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		word, freq, _ := strings.Cut(line, "\t")
+		f, _ := strconv.ParseFloat(freq, 64)
+		l.freq[word] = f
+	}
+	for _, line := range strings.Split(anglicismsData, "\n") {
+		if line != "" && !strings.HasPrefix(line, "#") {
+			l.anglicisms[line] = struct{}{}
+		}
+	}
+
+	return l
+}
+
+func (l *Lexicon) Analyze(cx *models.Complex) {
+	result := &models.Lexicon{}
+	for _, field := range models.TextFields {
+		for _, value := range cx.Values(field) {
+			for _, token := range tokenizer.Tokens(value) {
+				if token.Kind != tokenizer.Word {
+					continue
+				}
+				result.Words++
+				r, _ := utf8.DecodeRuneInString(token.Form)
+				if unicode.IsUpper(r) {
+					result.Nouns++
+					if parts := l.Split(token.Form); len(parts) > 1 {
+						result.Compounds = append(result.Compounds, models.Compound{Word: token.Form, Parts: parts})
+					}
+				}
+				if reason := l.Anglicism(token.Form); reason != "" {
+					result.Anglicisms = append(result.Anglicisms, models.Anglicism{Word: token.Form, Reason: reason})
+				}
+			}
+		}
+	}
+	cx.Analysis.Lexicon = result
+}
+
+// Split splits compound into lowercase parts choosing the split with the highest
+// geometric mean of part frequencies, returns nil when the word is better left whole
+func (l *Lexicon) Split(word string) []string {
+	word = strings.ToLower(word)
+	if strings.Contains(word, "-") {
+		parts := make([]string, 0)
+		for _, part := range strings.Split(word, "-") {
+			if part != "" {
+				parts = append(parts, part)
+			}
+		}
+		return parts
+	}
+	if utf8.RuneCountInString(word) < minCompound {
+		return nil
+	}
+
+	parts, score := l.split([]rune(word), maxParts)
+	if len(parts) < 2 || score <= l.lookup(word, true) {
+		return nil
+	}
+
+	return parts
+}
+
+func (l *Lexicon) split(word []rune, depth int) ([]string, float64) {
+	bestParts := []string{string(word)}
+	bestScore := l.lookup(string(word), true)
+	if depth == 1 {
+		return bestParts, bestScore
+	}
+
+	for i := minPart; i <= len(word)-minPart; i++ {
+		head := string(word[:i])
+		headFreq := l.lookup(head, false)
+		if headFreq == 0 {
+			for _, linker := range linkers {
+				if stem := strings.TrimSuffix(head, linker); stem != head && utf8.RuneCountInString(stem) >= minPart {
+					if headFreq = l.lookup(stem, false); headFreq > 0 {
+						head = stem
+						break
+					}
+				}
+			}
+		}
+		if headFreq == 0 {
+			continue
+		}
+
+		tailParts, tailScore := l.split(word[i:], depth-1)
+		if tailScore == 0 {
+			continue
+		}
+		n := float64(len(tailParts) + 1)
+		// geometric mean of part frequencies
+		score := math.Pow(headFreq*math.Pow(tailScore, n-1), 1/n)
+		if score > bestScore {
+			bestParts = append([]string{head}, tailParts...)
+			bestScore = score
+		}
+	}
+
+	return bestParts, bestScore
+}
+
+// lookup returns lexicon frequency, inflected tails are reduced by common endings
+func (l *Lexicon) lookup(word string, inflected bool) float64 {
+	if f, ok := l.freq[word]; ok {
+		return f
+	}
+	if inflected {
+		for _, ending := range endings {
+			stem := strings.TrimSuffix(word, ending)
+			if stem == word || utf8.RuneCountInString(stem) < minPart {
+				continue
+			}
+			if f, ok := l.freq[stem]; ok {
+				return f
+			}
+		}
+	}
+
+	return 0
+}
+
+// Anglicism returns why the word looks english or empty string
+func (l *Lexicon) Anglicism(word string) string {
+	lower := strings.ToLower(word)
+	if l.listed(lower) {
+		return "list"
+	}
+	for _, part := range strings.Split(lower, "-") {
+		if part != lower && l.listed(part) {
+			return "list"
+		}
+	}
+	// english head or tail of a german compound like "Lockdownregeln" and "Bahnmanager"
+	runes := []rune(lower)
+	for i := minPart; i <= len(runes)-minPart; i++ {
+		head, tail := string(runes[:i]), string(runes[i:])
+		if _, ok := l.anglicisms[head]; ok && i >= minAffix && l.lookup(tail, true) > 0 {
+			return "list"
+		}
+		if _, ok := l.anglicisms[tail]; ok && len(runes)-i >= minAffix && l.lookup(head, false) > 0 {
+			return "list"
+		}
+	}
+
+	if utf8.RuneCountInString(lower) < 4 || strings.Contains(lower, "-") {
+		return ""
+	}
+	hits := make([]string, 0)
+	for _, p := range englishPatterns {
+		if p.re.MatchString(lower) {
+			hits = append(hits, p.name)
+		}
+	}
+	if len(hits) >= 2 {
+		return "orthography:" + strings.Join(hits, ",")
+	}
+
+	return ""
+}
+
+// listed reports whether lower case word or its english plural is in the anglicisms list
+func (l *Lexicon) listed(word string) bool {
+	if _, ok := l.anglicisms[word]; ok {
+		return true
+	}
+	_, ok := l.anglicisms[strings.TrimSuffix(word, "s")]
+
+	return ok
+}
@@ -0,0 +1,62 @@
+package lexicon
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnglicism(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"Lockdown", "list"},
+		{"Lockdowns", "list"},
+		{"Corona-Lockdown", "list"},
+		{"Lockdownregeln", "list"},
+		{"Bahnmanager", "list"},
+		{"Influencerinnen", "list"},
+		{"Fans", "list"},
+		{"Teamchef", "list"},
+		// german words once matched by list entries or their prefixes
+		{"Band", ""},
+		{"Bandbreite", ""},
+		{"Boss", ""},
+		{"Album", ""},
+		{"Mailand", ""},
+		{"Fanta", ""},
+		{"Regierung", ""},
+		{"Showrunner", "orthography:sh,ow"},
+	}
+
+	l := New()
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := l.Anglicism(tt.word); got != tt.want {
+				t.Errorf("Anglicism(%q) = %q, want %q", tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		word string
+		want []string
+	}{
+		{"Parteichef", []string{"partei", "chef"}},
+		{"Bundesregierung", []string{"bund", "regierung"}},
+		{"CDU-Chef", []string{"cdu", "chef"}},
+		{"Regierung", nil},
+		{"Haus", nil},
+	}
+
+	l := New()
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := l.Split(tt.word); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split(%q) = %q, want %q", tt.word, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package readability
+
+import (
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/pkg/tokenizer"
+	"strings"
+	"unicode/utf8"
+)
+
+// longWord is the letter count above which a word counts as long in the Wiener Sachtextformel
+const longWord = 6
+
+type Readability struct {
+}
+
+func New() *Readability {
+	return &Readability{}
+}
+
+func (r *Readability) Analyze(cx *models.Complex) {
+	cx.Analysis.Readability = Measure(cx.Lead)
+	cx.Analysis.BodyReadability = Measure(Body(*cx)...)
+}
+
+// Body returns subtitles and image titles, the body text kept of an article
+func Body(cx models.Complex) []string {
+	body := make([]string, 0, len(cx.Subtitles)+len(cx.ImageTitles))
+
+	return append(append(body, cx.Subtitles...), cx.ImageTitles...)
+}
+
+// Measure computes readability scores of german texts, every text ends a sentence even without
+// a terminal mark like subtitles do, nil for texts without words
+func Measure(texts ...string) *models.Readability {
+	m := &models.Readability{}
+	long, polysyllabic, monosyllabic := 0, 0, 0
+	sentences := make([]tokenizer.Sentence, 0)
+	for _, text := range texts {
+		sentences = append(sentences, tokenizer.Tokenize(text)...)
+	}
+	for _, sentence := range sentences {
+		words := 0
+		for _, token := range sentence {
+			if token.Kind != tokenizer.Word {
+				continue
+			}
+			words++
+			syllables := Syllables(token.Form)
+			m.Syllables += syllables
+			if utf8.RuneCountInString(token.Form) > longWord {
+				long++
+			}
+			if syllables >= 3 {
+				polysyllabic++
+			}
+			if syllables == 1 {
+				monosyllabic++
+			}
+		}
+		if words > 0 {
+			m.Sentences++
+			m.Words += words
+		}
+	}
+	if m.Words == 0 {
+		return nil
+	}
+
+	words := float64(m.Words)
+	m.AvgSentenceLength = words / float64(m.Sentences)
+	m.SyllablesPerWord = float64(m.Syllables) / words
+	m.LongWordRatio = float64(long) / words
+	// Amstad's adaptation of Flesch Reading Ease for german
+	m.FleschAmstad = 180 - m.AvgSentenceLength - 58.5*m.SyllablesPerWord
+	// first Wiener Sachtextformel, percentages of words
+	m.WienerSachtext = 0.1935*100*float64(polysyllabic)/words + 0.1672*m.AvgSentenceLength +
+		0.1297*100*m.LongWordRatio - 0.0327*100*float64(monosyllabic)/words - 0.875
+
+	return m
+}
+
+// Syllables estimates syllable count as number of vowel groups, diphthongs count once
+func Syllables(word string) int {
+	count := 0
+	inVowel := false
+	for _, r := range strings.ToLower(word) {
+		if strings.ContainsRune("aeiouäöüy", r) {
+			if !inVowel {
+				count++
+			}
+			inVowel = true
+		} else {
+			inVowel = false
+		}
+	}
+	if count == 0 {
+		count = 1
+	}
+
+	return count
+}
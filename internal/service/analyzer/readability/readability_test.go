@@ -0,0 +1,124 @@
+package readability
+
+import (
+	"github.com/sku4/mslu-parser/models"
+	"math"
+	"testing"
+)
+
+func TestMeasure(t *testing.T) {
+	tests := []struct {
+		name  string
+		texts []string
+		want  *models.Readability
+	}{
+		{
+			// 4 words of one syllable: FA = 180 - 4 - 58.5*1, WSTF = 0.1672*4 - 0.0327*100 - 0.875
+			name:  "short sentence",
+			texts: []string{"Der Hund bellt laut."},
+			want: &models.Readability{
+				Sentences: 1, Words: 4, Syllables: 4, AvgSentenceLength: 4, SyllablesPerWord: 1,
+				FleschAmstad: 117.5, WienerSachtext: -3.4762,
+			},
+		},
+		{
+			// Die Bun-des-re-gie-rung ver-ab-schie-det das Ge-setz. Es gilt ab mor-gen.
+			// 9 words of 18 syllables, 2 of 3+ syllables and over 6 letters, 5 of one syllable
+			name:  "two sentences",
+			texts: []string{"Die Bundesregierung verabschiedet das Gesetz. Es gilt ab morgen."},
+			want: &models.Readability{
+				Sentences: 2, Words: 9, Syllables: 18, AvgSentenceLength: 4.5, SyllablesPerWord: 2,
+				LongWordRatio: 2.0 / 9, FleschAmstad: 58.5,
+				WienerSachtext: 0.1935*100*2/9 + 0.1672*4.5 + 0.1297*100*2/9 - 0.0327*100*5/9 - 0.875,
+			},
+		},
+		{
+			// Kri-tik aus der U-nion, Was kommt jetzt: every text ends a sentence
+			name:  "subtitles without terminal mark",
+			texts: []string{"Kritik aus der Union", "Was kommt jetzt?"},
+			want: &models.Readability{
+				Sentences: 2, Words: 7, Syllables: 9, AvgSentenceLength: 3.5, SyllablesPerWord: 9.0 / 7,
+				FleschAmstad:   180 - 3.5 - 58.5*9/7,
+				WienerSachtext: 0.1672*3.5 - 0.0327*100*5/7 - 0.875,
+			},
+		},
+		{"punctuation only", []string{"…", " "}, nil},
+		{"no texts", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Measure(tt.texts...)
+			if got == nil || tt.want == nil {
+				if got != tt.want {
+					t.Fatalf("Measure() = %+v, want %+v", got, tt.want)
+				}
+				return
+			}
+			if got.Sentences != tt.want.Sentences || got.Words != tt.want.Words || got.Syllables != tt.want.Syllables {
+				t.Errorf("Measure() counts = %d/%d/%d, want %d/%d/%d", got.Sentences, got.Words, got.Syllables,
+					tt.want.Sentences, tt.want.Words, tt.want.Syllables)
+			}
+			scores := []struct {
+				name      string
+				got, want float64
+			}{
+				{"avg sentence length", got.AvgSentenceLength, tt.want.AvgSentenceLength},
+				{"syllables per word", got.SyllablesPerWord, tt.want.SyllablesPerWord},
+				{"long word ratio", got.LongWordRatio, tt.want.LongWordRatio},
+				{"flesch amstad", got.FleschAmstad, tt.want.FleschAmstad},
+				{"wiener sachtext", got.WienerSachtext, tt.want.WienerSachtext},
+			}
+			for _, s := range scores {
+				if math.Abs(s.got-s.want) > 1e-9 {
+					t.Errorf("Measure() %s = %v, want %v", s.name, s.got, s.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSyllables(t *testing.T) {
+	tests := []struct {
+		word string
+		want int
+	}{
+		{"Hund", 1},
+		{"Haus", 1},
+		{"Leute", 2},
+		{"Gesetz", 2},
+		{"Bundesregierung", 5},
+		{"Übergänge", 4},
+		{"Tschüss", 1},
+		{"BRD", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := Syllables(tt.word); got != tt.want {
+				t.Errorf("Syllables(%q) = %d, want %d", tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	cx := models.Complex{
+		Lead:        "Der Hund bellt laut.",
+		Subtitles:   []string{"Kritik aus der Union"},
+		ImageTitles: []string{"Was kommt jetzt?"},
+	}
+	New().Analyze(&cx)
+	if r := cx.Analysis.Readability; r == nil || r.Words != 4 {
+		t.Errorf("Analyze() lead readability = %+v, want 4 words", r)
+	}
+	if r := cx.Analysis.BodyReadability; r == nil || r.Sentences != 2 || r.Words != 7 {
+		t.Errorf("Analyze() body readability = %+v, want 2 sentences of 7 words", r)
+	}
+
+	cx = models.Complex{Lead: "Der Hund bellt laut."}
+	New().Analyze(&cx)
+	if cx.Analysis.BodyReadability != nil {
+		t.Errorf("Analyze() body readability without body = %+v, want nil", cx.Analysis.BodyReadability)
+	}
+}
@@ -0,0 +1,159 @@
+package corpus
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/sku4/mslu-parser/internal/repository"
+	"github.com/sku4/mslu-parser/models"
+	"sort"
+	"strings"
+)
+
+const (
+	defaultPerPage = 50
+	maxPerPage     = 500
+)
+
+// Service serves articles of the repository read-only
+type Service struct {
+	repos *repository.Repository
+}
+
+func NewService(repos *repository.Repository) *Service {
+	return &Service{
+		repos: repos,
+	}
+}
+
+// Articles returns page of articles passing query, newest first, articles without date last
+func (s *Service) Articles(ctx context.Context, query models.ArticleQuery) (models.ArticlePage, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PerPage < 1 {
+		query.PerPage = defaultPerPage
+	}
+	if query.PerPage > maxPerPage {
+		query.PerPage = maxPerPage
+	}
+
+	var (
+		complexes []models.Complex
+		err       error
+	)
+	if strings.TrimSpace(query.Contains) != "" {
+		complexes, err = s.repos.Excel.Search(ctx, query.Contains, query.Fields)
+	} else {
+		complexes, err = s.repos.Excel.GetComplexes(ctx)
+	}
+	if err != nil {
+		return models.ArticlePage{}, err
+	}
+
+	filtered := make([]models.Complex, 0, len(complexes))
+	for _, cx := range query.Filter.Apply(complexes) {
+		if query.HasCaptions != nil && (len(cx.ImageTitles) > 0) != *query.HasCaptions {
+			continue
+		}
+		filtered = append(filtered, cx)
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		a, b := filtered[i].Date, filtered[j].Date
+		if a.IsZero() != b.IsZero() {
+			return b.IsZero()
+		}
+		if !a.Equal(b) {
+			return a.After(b)
+		}
+		return filtered[i].Url < filtered[j].Url
+	})
+
+	// page is checked before multiplied by per page to not overflow, no articles make one empty page
+	pages := (len(filtered) + query.PerPage - 1) / query.PerPage
+	if pages == 0 {
+		pages = 1
+	}
+	if query.Page > pages {
+		return models.ArticlePage{}, models.PageOutOfRangeError
+	}
+	page := models.ArticlePage{
+		Total:    len(filtered),
+		Page:     query.Page,
+		PerPage:  query.PerPage,
+		Articles: make([]models.Article, 0, query.PerPage),
+	}
+	start := (query.Page - 1) * query.PerPage
+	for i := start; i < len(filtered) && i < start+query.PerPage; i++ {
+		page.Articles = append(page.Articles, models.NewArticle(filtered[i]))
+	}
+
+	return page, nil
+}
+
+// Article returns article of id with analysis and replaced versions
+func (s *Service) Article(ctx context.Context, id string) (models.Article, error) {
+	cx, err := s.repos.Excel.GetComplex(ctx, id)
+	if err != nil {
+		return models.Article{}, err
+	}
+
+	article := models.NewArticle(cx)
+	analysis := cx.Analysis
+	article.Analysis = &analysis
+	if article.Versions, err = s.repos.Excel.GetVersions(ctx, cx.Url); err != nil {
+		return models.Article{}, err
+	}
+
+	return article, nil
+}
+
+// Counts returns article counts grouped by comma separated dimensions outlet and month
+func (s *Service) Counts(ctx context.Context, by string, filter models.Filter) ([]models.Count, error) {
+	byOutlet, byMonth := false, false
+	for _, name := range strings.Split(by, ",") {
+		switch strings.TrimSpace(name) {
+		case "outlet":
+			byOutlet = true
+		case "month":
+			byMonth = true
+		case "":
+		default:
+			return nil, errors.New(fmt.Sprintf("unknown dimension '%s'", name))
+		}
+	}
+
+	complexes, err := s.repos.Excel.GetComplexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[models.Count]int)
+	for _, cx := range filter.Apply(complexes) {
+		key := models.Count{}
+		if byOutlet {
+			key.Outlet = cx.Profile
+		}
+		if byMonth {
+			key.Month = "unknown"
+			if !cx.Date.IsZero() {
+				key.Month = cx.Date.Format("2006-01")
+			}
+		}
+		counts[key]++
+	}
+
+	result := make([]models.Count, 0, len(counts))
+	for key, count := range counts {
+		key.Articles = count
+		result = append(result, key)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Outlet != result[j].Outlet {
+			return result[i].Outlet < result[j].Outlet
+		}
+		return result[i].Month < result[j].Month
+	})
+
+	return result, nil
+}
@@ -0,0 +1,57 @@
+package corpus
+
+import (
+	"context"
+	"errors"
+	"github.com/sku4/mslu-parser/internal/repository"
+	"github.com/sku4/mslu-parser/models"
+	"strconv"
+	"testing"
+)
+
+type fakeExcel struct {
+	repository.Excel
+	complexes []models.Complex
+}
+
+func (e fakeExcel) GetComplexes(context.Context) ([]models.Complex, error) {
+	return e.complexes, nil
+}
+
+func TestArticlesPages(t *testing.T) {
+	tests := []struct {
+		name     string
+		rows     int
+		page     int
+		perPage  int
+		articles int
+		err      error
+	}{
+		{"full last page", 100, 2, 50, 50, nil},
+		{"after full last page", 100, 3, 50, 0, models.PageOutOfRangeError},
+		{"partial last page", 101, 3, 50, 1, nil},
+		{"after partial last page", 101, 4, 50, 0, models.PageOutOfRangeError},
+		{"no rows", 0, 1, 50, 0, nil},
+		{"after no rows", 0, 2, 50, 0, models.PageOutOfRangeError},
+		{"default per page", 60, 2, 0, 10, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			excel := fakeExcel{}
+			for i := 0; i < tt.rows; i++ {
+				excel.complexes = append(excel.complexes, models.Complex{
+					ExcelUrl: models.ExcelUrl{Url: "https://example.com/" + strconv.Itoa(i)},
+				})
+			}
+			s := NewService(&repository.Repository{Excel: excel})
+			page, err := s.Articles(context.Background(), models.ArticleQuery{Page: tt.page, PerPage: tt.perPage})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Articles() error = %v, want %v", err, tt.err)
+			}
+			if len(page.Articles) != tt.articles {
+				t.Errorf("Articles() page has %d articles, want %d", len(page.Articles), tt.articles)
+			}
+		})
+	}
+}
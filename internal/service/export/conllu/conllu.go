@@ -0,0 +1,76 @@
+package conllu
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/pkg/tokenizer"
+	"io"
+	"strings"
+)
+
+type Conllu struct {
+}
+
+func New() *Conllu {
+	return &Conllu{}
+}
+
+// Write writes tokenized text fields in CoNLL-U, annotation columns are left empty
+func (c *Conllu) Write(w io.Writer, complexes []models.Complex, fields []models.Field) error {
+	bw := bufio.NewWriter(w)
+	for n, cx := range complexes {
+		_, _ = fmt.Fprintf(bw, "# newdoc id = doc%d\n", n+1)
+		_, _ = fmt.Fprintf(bw, "# url = %s\n", sanitize(cx.Url))
+		_, _ = fmt.Fprintf(bw, "# source = %s\n", cx.Profile)
+		if !cx.Date.IsZero() {
+			_, _ = fmt.Fprintf(bw, "# date = %s\n", cx.Date.Format("2006-01-02"))
+		}
+
+		p := 0
+		for _, field := range fields {
+			if !field.IsText() {
+				continue
+			}
+			for _, value := range cx.Values(field) {
+				sentences := tokenizer.Tokenize(value)
+				if len(sentences) == 0 {
+					continue
+				}
+				p++
+				_, _ = fmt.Fprintf(bw, "# newpar id = doc%d-p%d\n", n+1, p)
+				_, _ = fmt.Fprintf(bw, "# field = %s\n", field)
+				for i, sentence := range sentences {
+					_, _ = fmt.Fprintf(bw, "# sent_id = doc%d-p%d-s%d\n", n+1, p, i+1)
+					_, _ = fmt.Fprintf(bw, "# text = %s\n", sentence.Text())
+					for j, token := range sentence {
+						misc := "_"
+						if !token.SpaceAfter && j < len(sentence)-1 {
+							misc = "SpaceAfter=No"
+						}
+						_, _ = fmt.Fprintf(bw, "%d\t%s\t_\t%s\t_\t_\t_\t_\t_\t%s\n", j+1, token.Form, upos(token), misc)
+					}
+					_, _ = bw.WriteString("\n")
+				}
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+func upos(token tokenizer.Token) string {
+	switch token.Kind {
+	case tokenizer.Punct:
+		return "PUNCT"
+	case tokenizer.Number:
+		return "NUM"
+	}
+
+	return "_"
+}
+
+// sanitize keeps comment values on a single line
+func sanitize(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
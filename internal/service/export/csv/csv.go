@@ -0,0 +1,44 @@
+package csv
+
+import (
+	"encoding/csv"
+	"github.com/sku4/mslu-parser/models"
+	"io"
+	"strings"
+)
+
+type Csv struct {
+	joiner string
+}
+
+func New(joiner string) *Csv {
+	return &Csv{
+		joiner: joiner,
+	}
+}
+
+// Write writes RFC 4180 csv with header row, list fields are joined with joiner
+func (c *Csv) Write(w io.Writer, complexes []models.Complex, fields []models.Field) error {
+	cw := csv.NewWriter(w)
+	cw.UseCRLF = true
+
+	record := make([]string, len(fields))
+	for i, field := range fields {
+		record[i] = string(field)
+	}
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+
+	for _, cx := range complexes {
+		for i, field := range fields {
+			record[i] = strings.Join(cx.Values(field), c.joiner)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/sku4/mslu-parser/internal/repository"
+	"github.com/sku4/mslu-parser/internal/service/export/conllu"
+	"github.com/sku4/mslu-parser/internal/service/export/csv"
+	"github.com/sku4/mslu-parser/internal/service/export/jsonl"
+	"github.com/sku4/mslu-parser/internal/service/export/tei"
+	"github.com/sku4/mslu-parser/internal/service/export/vertical"
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/models/cli"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+//go:generate mockgen -source=export.go -destination=mocks/export.go
+
+type iFormat interface {
+	Write(w io.Writer, complexes []models.Complex, fields []models.Field) error
+}
+
+type Service struct {
+	repos *repository.Repository
+}
+
+func NewService(repos *repository.Repository) *Service {
+	return &Service{
+		repos: repos,
+	}
+}
+
+func (s *Service) Export(ctx context.Context, w io.Writer) error {
+	args := cli.GetExportArgs(ctx)
+
+	var format iFormat
+	switch args.Format {
+	case "jsonl":
+		format = jsonl.New()
+	case "csv":
+		format = csv.New(args.Joiner)
+	case "tei":
+		format = tei.New(!args.Split)
+	case "conllu":
+		format = conllu.New()
+	case "vertical":
+		format = vertical.New()
+	default:
+		return errors.New(fmt.Sprintf("Format '%s' not found", args.Format))
+	}
+
+	fields, err := models.ParseFields(args.Fields)
+	if err != nil {
+		return err
+	}
+
+	filter, err := models.NewFilter(args.Profile, args.From, args.To)
+	if err != nil {
+		return err
+	}
+	filter.Unique = args.Unique
+	if len(args.Urls) > 0 {
+		filter.Urls = make(map[string]struct{}, len(args.Urls))
+		for _, url := range args.Urls {
+			filter.Urls[url] = struct{}{}
+		}
+	}
+
+	var complexes []models.Complex
+	if args.Match != "" {
+		matchFields, err := models.ParseFields(args.MatchFields)
+		if err != nil {
+			return err
+		}
+		complexes, err = s.repos.Excel.Search(ctx, args.Match, matchFields)
+		if err != nil {
+			return err
+		}
+	} else if complexes, err = s.repos.Excel.GetComplexes(ctx); err != nil {
+		return err
+	}
+
+	filtered := filter.Apply(complexes)
+
+	if args.Split {
+		return s.exportSplit(args, format, filtered, fields)
+	}
+
+	if err = format.Write(w, filtered, fields); err != nil {
+		return errors.Wrap(err, "Export write")
+	}
+
+	return nil
+}
+
+// exportSplit writes every complex to its own file in output directory
+func (s *Service) exportSplit(args cli.ExportArguments, format iFormat, complexes []models.Complex,
+	fields []models.Field) error {
+	if args.Output == "" {
+		return errors.New("output directory not set")
+	}
+	if err := os.MkdirAll(args.Output, 0o755); err != nil {
+		return errors.Wrap(err, "create output directory")
+	}
+
+	for i, cx := range complexes {
+		name := filepath.Join(args.Output, fmt.Sprintf("%06d.%s", i+1, Extension(args.Format)))
+		f, err := os.Create(name)
+		if err != nil {
+			return errors.Wrap(err, "create output file")
+		}
+		err = format.Write(f, []models.Complex{cx}, fields)
+		_ = f.Close()
+		if err != nil {
+			return errors.Wrap(err, "Export write")
+		}
+	}
+
+	return nil
+}
+
+// Extension returns file extension of export format
+func Extension(format string) string {
+	switch format {
+	case "tei":
+		return "xml"
+	case "vertical":
+		return "vert"
+	}
+
+	return format
+}
@@ -0,0 +1,61 @@
+package jsonl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"github.com/sku4/mslu-parser/models"
+	"io"
+)
+
+type Jsonl struct {
+}
+
+func New() *Jsonl {
+	return &Jsonl{}
+}
+
+// Write writes one json object per complex, keys keep the order of fields
+func (j *Jsonl) Write(w io.Writer, complexes []models.Complex, fields []models.Field) error {
+	bw := bufio.NewWriter(w)
+	var line bytes.Buffer
+	for _, cx := range complexes {
+		line.Reset()
+		line.WriteByte('{')
+		for i, field := range fields {
+			if i > 0 {
+				line.WriteByte(',')
+			}
+			key, _ := json.Marshal(string(field))
+			line.Write(key)
+			line.WriteByte(':')
+
+			var value interface{}
+			values := cx.Values(field)
+			if field == models.FieldAnalysis {
+				value = cx.Analysis
+			} else if field == models.FieldRaw {
+				value = cx.Raw
+			} else if field.IsList() {
+				if values == nil {
+					values = []string{}
+				}
+				value = values
+			} else {
+				value = values[0]
+			}
+			b, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			line.Write(b)
+		}
+		line.WriteString("}\n")
+
+		if _, err := bw.Write(line.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,206 @@
+package tei
+
+import (
+	"encoding/xml"
+	"github.com/sku4/mslu-parser/models"
+	"io"
+)
+
+const (
+	namespace   = "http://www.tei-c.org/ns/1.0"
+	publication = "Converted by mslu-parser for research and teaching purposes"
+)
+
+type Tei struct {
+	corpus bool
+}
+
+// New creates TEI P5 writer, corpus wraps all articles into one teiCorpus document
+func New(corpus bool) *Tei {
+	return &Tei{
+		corpus: corpus,
+	}
+}
+
+type header struct {
+	XMLName  xml.Name `xml:"teiHeader"`
+	FileDesc fileDesc `xml:"fileDesc"`
+}
+
+type fileDesc struct {
+	TitleStmt       titleStmt       `xml:"titleStmt"`
+	PublicationStmt publicationStmt `xml:"publicationStmt"`
+	SourceDesc      sourceDesc      `xml:"sourceDesc"`
+}
+
+type titleStmt struct {
+	Title  string `xml:"title"`
+	Author string `xml:"author,omitempty"`
+}
+
+type publicationStmt struct {
+	P string `xml:"p"`
+}
+
+type sourceDesc struct {
+	Bibl *bibl  `xml:"bibl,omitempty"`
+	P    string `xml:"p,omitempty"`
+}
+
+type bibl struct {
+	Title     string `xml:"title"`
+	Author    string `xml:"author,omitempty"`
+	Publisher string `xml:"publisher,omitempty"`
+	Date      *date  `xml:"date"`
+	Ref       ref    `xml:"ref"`
+}
+
+type date struct {
+	When  string `xml:"when,attr"`
+	Value string `xml:",chardata"`
+}
+
+type ref struct {
+	Target string `xml:"target,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type document struct {
+	XMLName xml.Name `xml:"TEI"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
+	Header  header
+	Text    text `xml:"text"`
+}
+
+type text struct {
+	Div div `xml:"body>div"`
+}
+
+type div struct {
+	Type    string   `xml:"type,attr"`
+	Heads   []head   `xml:"head"`
+	P       []p      `xml:"p"`
+	Figures []figure `xml:"figure"`
+}
+
+type head struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type p struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type figure struct {
+	FigDesc string `xml:"figDesc"`
+}
+
+type corpus struct {
+	XMLName   xml.Name `xml:"teiCorpus"`
+	Xmlns     string   `xml:"xmlns,attr"`
+	Header    header
+	Documents []document
+}
+
+// Write writes TEI documents, fields are ignored because TEI layout is fixed
+func (t *Tei) Write(w io.Writer, complexes []models.Complex, _ []models.Field) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if t.corpus {
+		c := corpus{
+			Xmlns: namespace,
+			Header: header{
+				FileDesc: fileDesc{
+					TitleStmt:       titleStmt{Title: "mslu-parser corpus"},
+					PublicationStmt: publicationStmt{P: publication},
+					SourceDesc:      sourceDesc{P: "Articles collected from online news outlets"},
+				},
+			},
+			Documents: make([]document, 0, len(complexes)),
+		}
+		for _, cx := range complexes {
+			c.Documents = append(c.Documents, newDocument(cx, ""))
+		}
+		if err := enc.Encode(c); err != nil {
+			return err
+		}
+	} else {
+		for _, cx := range complexes {
+			if err := enc.Encode(newDocument(cx, namespace)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func newDocument(cx models.Complex, xmlns string) document {
+	title := cx.Title
+	if title == "" {
+		title = cx.Url
+	}
+
+	var d *date
+	if !cx.Date.IsZero() {
+		d = &date{
+			When:  cx.Date.Format("2006-01-02"),
+			Value: cx.Date.Format("02.01.2006"),
+		}
+	}
+
+	body := div{
+		Type: "article",
+	}
+	if cx.OverTitle != "" {
+		body.Heads = append(body.Heads, head{Type: "overtitle", Value: cx.OverTitle})
+	}
+	body.Heads = append(body.Heads, head{Type: "main", Value: cx.Title})
+	for _, subtitle := range cx.Subtitles {
+		body.Heads = append(body.Heads, head{Type: "subtitle", Value: subtitle})
+	}
+	if cx.Lead != "" {
+		body.P = append(body.P, p{Type: "lead", Value: cx.Lead})
+	}
+	for _, imageTitle := range cx.ImageTitles {
+		body.Figures = append(body.Figures, figure{FigDesc: imageTitle})
+	}
+
+	return document{
+		Xmlns: xmlns,
+		Header: header{
+			FileDesc: fileDesc{
+				TitleStmt: titleStmt{
+					Title:  title,
+					Author: cx.Author,
+				},
+				PublicationStmt: publicationStmt{P: publication},
+				SourceDesc: sourceDesc{
+					Bibl: &bibl{
+						Title:     title,
+						Author:    cx.Author,
+						Publisher: cx.Profile,
+						Date:      d,
+						Ref: ref{
+							Target: cx.Url,
+							Value:  cx.Url,
+						},
+					},
+				},
+			},
+		},
+		Text: text{
+			Div: body,
+		},
+	}
+}
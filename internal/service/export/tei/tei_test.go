@@ -0,0 +1,221 @@
+package tei
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/sku4/mslu-parser/models"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// node is an element of exported document with resolved namespace
+type node struct {
+	XMLName xml.Name
+	Nodes   []node `xml:",any"`
+	Text    string `xml:",chardata"`
+}
+
+func (n node) children() []string {
+	names := make([]string, 0, len(n.Nodes))
+	for _, child := range n.Nodes {
+		names = append(names, child.XMLName.Local)
+	}
+
+	return names
+}
+
+func (n node) child(name string) *node {
+	for i := range n.Nodes {
+		if n.Nodes[i].XMLName.Local == name {
+			return &n.Nodes[i]
+		}
+	}
+
+	return nil
+}
+
+var complexes = []models.Complex{
+	{
+		ExcelUrl:    models.ExcelUrl{Url: "https://www.zeit.de/politik/a?x=1&y=2"},
+		Profile:     "zeit",
+		Date:        time.Date(2023, 3, 14, 10, 0, 0, 0, time.UTC),
+		Author:      "Jörg Müller",
+		Title:       "Scholz & die „Zeitenwende“ <Analyse>",
+		OverTitle:   "Bundestag",
+		Lead:        "Der Kanzler spricht über Größe und Verantwortung.",
+		Subtitles:   []string{"Kritik aus der Union", "Was kommt jetzt?"},
+		ImageTitles: []string{"Olaf Scholz am 14. März in Berlin"},
+	},
+	{
+		ExcelUrl: models.ExcelUrl{Url: "https://www.spiegel.de/a2"},
+		Profile:  "spiegel",
+	},
+}
+
+func TestWriteDocument(t *testing.T) {
+	// split export writes every article to its own document
+	for _, cx := range complexes {
+		var b bytes.Buffer
+		if err := New(false).Write(&b, []models.Complex{cx}, nil); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		root := parse(t, b.Bytes())
+		if root.XMLName.Local != "TEI" {
+			t.Fatalf("root = %s, want TEI", root.XMLName.Local)
+		}
+		checkDocument(t, root)
+	}
+}
+
+func TestWriteCorpus(t *testing.T) {
+	var b bytes.Buffer
+	if err := New(true).Write(&b, complexes, nil); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	root := parse(t, b.Bytes())
+	if root.XMLName.Local != "teiCorpus" {
+		t.Fatalf("root = %s, want teiCorpus", root.XMLName.Local)
+	}
+	names := root.children()
+	if len(names) != len(complexes)+1 || names[0] != "teiHeader" {
+		t.Fatalf("teiCorpus children = %v, want teiHeader and %d TEI", names, len(complexes))
+	}
+	checkHeader(t, root.Nodes[0])
+	for _, doc := range root.Nodes[1:] {
+		if doc.XMLName.Local != "TEI" {
+			t.Fatalf("teiCorpus child = %s, want TEI", doc.XMLName.Local)
+		}
+		checkDocument(t, doc)
+	}
+}
+
+func TestWriteEscaping(t *testing.T) {
+	var b bytes.Buffer
+	if err := New(false).Write(&b, complexes[:1], nil); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	root := parse(t, b.Bytes())
+	found := false
+	for _, h := range root.child("text").child("body").child("div").Nodes {
+		found = found || h.XMLName.Local == "head" && h.Text == complexes[0].Title
+	}
+	if !found {
+		t.Fatalf("head with title %q not found", complexes[0].Title)
+	}
+}
+
+// parse checks document is well-formed with a single root element of TEI namespace
+func parse(t *testing.T, data []byte) node {
+	t.Helper()
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	depth, roots := 0, 0
+	for {
+		token, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("document is not well-formed: %v", err)
+		}
+		switch el := token.(type) {
+		case xml.StartElement:
+			if el.Name.Space != namespace {
+				t.Fatalf("element %s in namespace %q, want %q", el.Name.Local, el.Name.Space, namespace)
+			}
+			if depth == 0 {
+				roots++
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+	if roots != 1 {
+		t.Fatalf("document has %d root elements, want 1", roots)
+	}
+
+	var root node
+	if err := xml.Unmarshal(data, &root); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	return root
+}
+
+// checkDocument checks TEI content model: teiHeader followed by text with body
+func checkDocument(t *testing.T, doc node) {
+	t.Helper()
+	names := doc.children()
+	if len(names) != 2 || names[0] != "teiHeader" || names[1] != "text" {
+		t.Fatalf("TEI children = %v, want [teiHeader text]", names)
+	}
+	checkHeader(t, doc.Nodes[0])
+	body := doc.Nodes[1].child("body")
+	if body == nil || len(body.Nodes) == 0 {
+		t.Fatal("text has no body content")
+	}
+}
+
+// checkHeader checks fileDesc with titleStmt, publicationStmt and sourceDesc in order
+func checkHeader(t *testing.T, header node) {
+	t.Helper()
+	if names := header.children(); len(names) == 0 || names[0] != "fileDesc" {
+		t.Fatalf("teiHeader children = %v, want fileDesc first", names)
+	}
+	fileDesc := header.Nodes[0]
+	names := fileDesc.children()
+	if len(names) != 3 || names[0] != "titleStmt" || names[1] != "publicationStmt" || names[2] != "sourceDesc" {
+		t.Fatalf("fileDesc children = %v, want [titleStmt publicationStmt sourceDesc]", names)
+	}
+	if title := fileDesc.Nodes[0].child("title"); title == nil || title.Text == "" {
+		t.Fatal("titleStmt has no title")
+	}
+	if len(fileDesc.Nodes[1].Nodes) == 0 {
+		t.Fatal("publicationStmt is empty")
+	}
+	if len(fileDesc.Nodes[2].Nodes) == 0 {
+		t.Fatal("sourceDesc is empty")
+	}
+}
+
+// TestWriteSchema validates exported documents against the vendored TEI subset with xmllint,
+// the test is skipped where xmllint is not installed
+func TestWriteSchema(t *testing.T) {
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Skip("xmllint not installed")
+	}
+
+	tests := []struct {
+		name   string
+		corpus bool
+	}{
+		{"document", false},
+		{"corpus", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			cxs := complexes
+			if !tt.corpus {
+				cxs = complexes[:1]
+			}
+			if err := New(tt.corpus).Write(&b, cxs, nil); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			path := filepath.Join(t.TempDir(), "export.xml")
+			if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			out, err := exec.Command(xmllint, "--noout", "--relaxng", filepath.Join("testdata", "tei_mslu.rng"), path).CombinedOutput()
+			if err != nil {
+				t.Errorf("xmllint: %v\n%s", err, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package vertical
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/pkg/tokenizer"
+	"html"
+	"io"
+)
+
+type Vertical struct {
+}
+
+func New() *Vertical {
+	return &Vertical{}
+}
+
+// Write writes tokenized text fields in Sketch Engine vertical format, missing spaces are marked with <g/>
+func (v *Vertical) Write(w io.Writer, complexes []models.Complex, fields []models.Field) error {
+	bw := bufio.NewWriter(w)
+	for n, cx := range complexes {
+		date, month, year := "", "", ""
+		if !cx.Date.IsZero() {
+			date = cx.Date.Format("2006-01-02")
+			month = cx.Date.Format("2006-01")
+			year = cx.Date.Format("2006")
+		}
+		_, _ = fmt.Fprintf(bw, "<doc id=\"doc%d\" url=\"%s\" source=\"%s\" date=\"%s\" month=\"%s\" year=\"%s\">\n",
+			n+1, html.EscapeString(cx.Url), html.EscapeString(cx.Profile), date, month, year)
+
+		for _, field := range fields {
+			if !field.IsText() {
+				continue
+			}
+			for _, value := range cx.Values(field) {
+				sentences := tokenizer.Tokenize(value)
+				if len(sentences) == 0 {
+					continue
+				}
+				_, _ = fmt.Fprintf(bw, "<p type=\"%s\">\n", field)
+				for _, sentence := range sentences {
+					_, _ = bw.WriteString("<s>\n")
+					for j, token := range sentence {
+						_, _ = bw.WriteString(html.EscapeString(token.Form))
+						_, _ = bw.WriteString("\n")
+						if !token.SpaceAfter && j < len(sentence)-1 {
+							_, _ = bw.WriteString("<g/>\n")
+						}
+					}
+					_, _ = bw.WriteString("</s>\n")
+				}
+				_, _ = bw.WriteString("</p>\n")
+			}
+		}
+		_, _ = bw.WriteString("</doc>\n")
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,209 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/sku4/mslu-parser/internal/repository"
+	"github.com/sku4/mslu-parser/internal/service/export"
+	"github.com/sku4/mslu-parser/internal/service/parser"
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/models/cli"
+	"github.com/sku4/mslu-parser/pkg/logger"
+	"io"
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -source=job.go -destination=mocks/job.go
+
+// queueSize is the count of pending jobs accepted
+const queueSize = 100
+
+type iAnalyzer interface {
+	Analyze(*models.Complex)
+}
+
+type job struct {
+	models.Job
+	parser   *parser.Service
+	cancel   context.CancelFunc
+	canceled bool
+}
+
+// Service runs crawl jobs one at a time, articles of concurrent jobs would share rows of the storage
+type Service struct {
+	repos    *repository.Repository
+	analyzer iAnalyzer
+	rwMutex  *sync.RWMutex
+	jobs     map[string]*job
+	order    []string
+	queue    chan *job
+}
+
+func NewService(repos *repository.Repository, analyzer iAnalyzer) *Service {
+	return &Service{
+		repos:    repos,
+		analyzer: analyzer,
+		rwMutex:  &sync.RWMutex{},
+		jobs:     make(map[string]*job),
+		queue:    make(chan *job, queueSize),
+	}
+}
+
+// Create queues crawl job with arguments
+func (s *Service) Create(args cli.Arguments) (models.Job, error) {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	j := &job{
+		Job: models.Job{
+			ID:        cli.NewRunID(),
+			Status:    models.JobPending,
+			Arguments: args,
+			Created:   time.Now(),
+		},
+	}
+	select {
+	case s.queue <- j:
+	default:
+		return models.Job{}, errors.New(fmt.Sprintf("too many pending jobs, limit %d", queueSize))
+	}
+	s.jobs[j.ID] = j
+	s.order = append(s.order, j.ID)
+
+	return s.view(j), nil
+}
+
+// List returns jobs in order of creation
+func (s *Service) List() []models.Job {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	jobs := make([]models.Job, 0, len(s.order))
+	for _, id := range s.order {
+		jobs = append(jobs, s.view(s.jobs[id]))
+	}
+
+	return jobs
+}
+
+func (s *Service) Get(id string) (models.Job, error) {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	j, ok := s.jobs[id]
+	if !ok {
+		return models.Job{}, models.JobNotFoundError
+	}
+
+	return s.view(j), nil
+}
+
+// Cancel stops running job through its context, pending job is not started
+func (s *Service) Cancel(id string) (models.Job, error) {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	j, ok := s.jobs[id]
+	if !ok {
+		return models.Job{}, models.JobNotFoundError
+	}
+	switch j.Status {
+	case models.JobPending:
+		j.canceled = true
+		j.Status = models.JobCanceled
+		finished := time.Now()
+		j.Finished = &finished
+	case models.JobRunning:
+		j.canceled = true
+		j.cancel()
+	}
+
+	return s.view(j), nil
+}
+
+// Export writes articles saved by job in format of export arguments
+func (s *Service) Export(ctx context.Context, id string, w io.Writer) error {
+	j, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+	if j.Report == nil || len(j.Report.SavedUrls) == 0 {
+		return errors.New(fmt.Sprintf("job %s saved no articles", id))
+	}
+
+	args := cli.GetExportArgs(ctx)
+	args.Urls = j.Report.SavedUrls
+	args.Split = false
+
+	return export.NewService(s.repos).Export(cli.SetExportArgs(ctx, args), w)
+}
+
+// Process runs queued jobs until context is done
+func (s *Service) Process(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case j := <-s.queue:
+			s.run(ctx, j)
+		}
+	}
+}
+
+func (s *Service) run(ctx context.Context, j *job) {
+	s.rwMutex.Lock()
+	if j.canceled {
+		s.rwMutex.Unlock()
+		return
+	}
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	j.cancel = cancel
+	j.parser = parser.NewService(s.repos, s.analyzer)
+	j.Status = models.JobRunning
+	started := time.Now()
+	j.Started = &started
+	s.rwMutex.Unlock()
+
+	jobCtx = cli.SetArgs(jobCtx, j.Arguments)
+	jobCtx = cli.SetRunID(jobCtx, j.ID)
+	jobCtx = logger.WithContext(jobCtx, "run_id", j.ID)
+	log := logger.FromContext(jobCtx)
+	log.Infof("Job started with args: '%s', count %d, update %t", j.Arguments.Profile, j.Arguments.Count,
+		j.Arguments.Update)
+
+	err := j.parser.Run(jobCtx)
+	// shutdown waits for saving of downloaded articles
+	if shutdownErr := j.parser.Shutdown(); shutdownErr != nil && err == nil {
+		err = shutdownErr
+	}
+
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+	finished := time.Now()
+	j.Finished = &finished
+	j.Report = j.parser.Report()
+	switch {
+	case j.canceled || jobCtx.Err() != nil:
+		j.Status = models.JobCanceled
+	case err != nil:
+		j.Status = models.JobFailed
+		j.Error = err.Error()
+	default:
+		j.Status = models.JobDone
+	}
+	log.Infof("Job %s", j.Status)
+}
+
+// view returns copy of job with password redacted and live report of running job
+func (s *Service) view(j *job) models.Job {
+	view := j.Job
+	view.Arguments.Password = ""
+	if j.Status == models.JobRunning && j.parser != nil {
+		view.Report = j.parser.Report()
+	}
+
+	return view
+}
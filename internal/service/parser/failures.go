@@ -0,0 +1,168 @@
+package parser
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/models/cli"
+	"github.com/sku4/mslu-parser/pkg/logger"
+	"hash/crc32"
+	"sync"
+	"time"
+)
+
+const (
+	// deadLetterDelay is the delay before the first retry of an url in a later run
+	deadLetterDelay    = time.Hour
+	maxDeadLetterDelay = 7 * 24 * time.Hour
+)
+
+// queueFailures queues urls of profile snapshots and dead letters instead of searching new articles,
+// dead letters failed by status 4xx, parse or paywall are skipped by search and retried here only
+func (s *Service) queueFailures(ctx context.Context, wg *sync.WaitGroup) error {
+	defer wg.Done()
+	defer close(s.urlsChan)
+	log := logger.FromContext(ctx)
+	args := cli.GetArgs(ctx)
+
+	urls := make([]string, 0)
+	if s.repos.Failures != nil {
+		snapshots, err := s.repos.Failures.List(ctx, args.Profile)
+		if err != nil {
+			log.Errorf("List failures error: %s", err.Error())
+			return err
+		}
+		for _, snapshot := range snapshots {
+			urls = append(urls, snapshot.Url)
+		}
+	}
+	if s.repos.DeadLetters != nil {
+		letters, err := s.repos.DeadLetters.List(ctx, args.Profile)
+		if err != nil {
+			log.Errorf("List dead letters error: %s", err.Error())
+			return err
+		}
+		for _, letter := range letters {
+			urls = append(urls, letter.Url)
+		}
+	}
+
+	log.Infof("Retry %d failed articles", len(urls))
+	queued := make(map[string]struct{}, len(urls))
+	for _, url := range urls {
+		if _, ok := queued[url]; ok {
+			continue
+		}
+		queued[url] = struct{}{}
+		excelUrl := models.ExcelUrl{
+			Url:      url,
+			ExcelRow: s.urls[crc32.Checksum([]byte(url), s.crcTable)],
+		}
+		if !s.queue(ctx, excelUrl) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// saveSnapshot keeps response of article failed extraction, errors without snapshot are ignored
+func (s *Service) saveSnapshot(ctx context.Context, err error) {
+	var snapshotErr *models.SnapshotError
+	if s.repos.Failures == nil || !errors.As(err, &snapshotErr) {
+		return
+	}
+
+	snapshot := *snapshotErr.Snapshot
+	snapshot.Profile = cli.GetArgs(ctx).Profile
+	snapshot.Error = err.Error()
+	snapshot.Time = time.Now()
+	// session cookies are not kept with the snapshot
+	snapshot.Header = snapshot.Header.Clone()
+	snapshot.Header.Del("Set-Cookie")
+	if err = s.repos.Failures.Save(ctx, snapshot); err != nil {
+		logger.FromContext(ctx).Errorf("Save failure snapshot error: %s", err.Error())
+	}
+}
+
+// removeSnapshot drops snapshot of article extracted successfully
+func (s *Service) removeSnapshot(ctx context.Context, url string) {
+	if s.repos.Failures == nil {
+		return
+	}
+
+	if err := s.repos.Failures.Remove(ctx, cli.GetArgs(ctx).Profile, url); err != nil {
+		logger.FromContext(ctx).Errorf("Remove failure snapshot error: %s", err.Error())
+	}
+}
+
+// deadLetter reports whether url failed before and is not due for retry
+func (s *Service) deadLetter(ctx context.Context, url string) bool {
+	if s.repos.DeadLetters == nil {
+		return false
+	}
+
+	letter, err := s.repos.DeadLetters.Get(ctx, url)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Get dead letter error: %s", err.Error())
+		return false
+	}
+
+	return letter != nil && !letter.Due(time.Now())
+}
+
+// putDeadLetter records url failed download, urls failed by status 5xx, network or unclassified
+// errors are due again after delay doubled per attempt
+func (s *Service) putDeadLetter(ctx context.Context, url string, attempts int, err error) {
+	if s.repos.DeadLetters == nil {
+		return
+	}
+	log := logger.FromContext(ctx)
+
+	letter, getErr := s.repos.DeadLetters.Get(ctx, url)
+	if getErr != nil {
+		log.Errorf("Get dead letter error: %s", getErr.Error())
+		return
+	}
+	now := time.Now()
+	if letter == nil {
+		letter = &models.DeadLetter{
+			Url:         url,
+			FirstFailed: now,
+		}
+	}
+	letter.Profile = cli.GetArgs(ctx).Profile
+	letter.Reason = models.ErrorClass(err)
+	letter.Error = err.Error()
+	letter.Attempts += attempts
+	letter.LastFailed = now
+	letter.NextRetry = time.Time{}
+	switch letter.Reason {
+	case models.ReasonClient, models.ReasonParse, models.ReasonPaywall:
+		// permanent failures are retried by retry-failed only
+	default:
+		delay := deadLetterDelay
+		for i := 1; i < letter.Attempts && delay < maxDeadLetterDelay; i++ {
+			delay *= 2
+		}
+		if delay > maxDeadLetterDelay {
+			delay = maxDeadLetterDelay
+		}
+		letter.NextRetry = now.Add(delay)
+	}
+
+	if err = s.repos.DeadLetters.Put(ctx, *letter); err != nil {
+		log.Errorf("Put dead letter error: %s", err.Error())
+	}
+}
+
+// removeDeadLetter drops url downloaded successfully
+func (s *Service) removeDeadLetter(ctx context.Context, url string) {
+	if s.repos.DeadLetters == nil {
+		return
+	}
+
+	if err := s.repos.DeadLetters.Remove(ctx, url); err != nil {
+		logger.FromContext(ctx).Errorf("Remove dead letter error: %s", err.Error())
+	}
+}
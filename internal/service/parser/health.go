@@ -0,0 +1,196 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/models/cli"
+	"github.com/sku4/mslu-parser/pkg/logger"
+	"github.com/sku4/mslu-parser/pkg/metrics"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// optionalFields are often empty in healthy articles, doctor warns only when none is filled
+var optionalFields = map[models.Field]bool{
+	models.FieldSubtitles:   true,
+	models.FieldImageTitles: true,
+	models.FieldAuthor:      true,
+}
+
+func filled(cx *models.Complex, field models.Field) bool {
+	return strings.TrimSpace(strings.Join(cx.Values(field), "")) != ""
+}
+
+// trackFill counts filled fields of downloaded article and aborts the run when fill rate
+// of a checked field over the last FillSample articles drops below the threshold
+func (s *Service) trackFill(ctx context.Context, cx *models.Complex) {
+	args := cli.GetArgs(ctx)
+	sample := args.FillSample
+	if sample < 1 {
+		sample = 1
+	}
+	var collapsed []string
+	s.updateReport(func(r *models.Report) {
+		r.Downloaded++
+		for _, field := range s.selectorFields() {
+			if _, ok := r.Filled[string(field)]; !ok {
+				r.Filled[string(field)] = 0
+			}
+			if filled(cx, field) {
+				r.Filled[string(field)]++
+				metrics.FilledFields.WithLabelValues(args.Profile, string(field)).Inc()
+			}
+		}
+
+		flags := make([]bool, len(s.fillFields))
+		for i, field := range s.fillFields {
+			if flags[i] = filled(cx, field); flags[i] {
+				s.fillCounts[i]++
+			}
+		}
+		s.fillWindow = append(s.fillWindow, flags)
+		if len(s.fillWindow) > sample {
+			for i, f := range s.fillWindow[0] {
+				if f {
+					s.fillCounts[i]--
+				}
+			}
+			s.fillWindow = s.fillWindow[1:]
+		}
+		if args.MinFillRate <= 0 || len(s.fillWindow) < sample {
+			return
+		}
+		for i, field := range s.fillFields {
+			if rate := float64(s.fillCounts[i]) / float64(len(s.fillWindow)); rate < args.MinFillRate {
+				collapsed = append(collapsed, fmt.Sprintf("%s %.2f", field, rate))
+			}
+		}
+	})
+	if len(collapsed) == 0 {
+		return
+	}
+
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+	if s.abortErr != nil {
+		return
+	}
+	s.abortErr = errors.New(fmt.Sprintf("fill rate below %.2f: %s, selectors may be broken",
+		args.MinFillRate, strings.Join(collapsed, ", ")))
+	logger.FromContext(ctx).Errorf("Abort run: %s", s.abortErr.Error())
+	s.abort()
+}
+
+// selectorFields returns fields with selectors of the profile in output order
+func (s *Service) selectorFields() []models.Field {
+	selectors := s.profile.Selectors()
+	fields := make([]models.Field, 0, len(selectors))
+	for _, field := range models.Fields {
+		if _, ok := selectors[field]; ok {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// Doctor downloads recent articles of profile and reports selectors returning nothing,
+// error is returned when a required field falls below the minimal fill rate
+func (s *Service) Doctor(ctx context.Context, w io.Writer) (err error) {
+	args := cli.GetArgs(ctx)
+	if args.Count < 1 {
+		return errors.New(fmt.Sprintf("count %d must be at least 1", args.Count))
+	}
+	if s.profile, err = newProfile(args.Profile); err != nil {
+		return err
+	}
+	log := logger.FromContext(ctx)
+	if err = s.profile.Auth(ctx); err != nil {
+		return err
+	}
+
+	urls := make([]models.ExcelUrl, 0, args.Count)
+	for pageNum := 1; len(urls) < args.Count; pageNum++ {
+		excelUrls, err := s.profile.SearchArticles(ctx, pageNum)
+		if err != nil {
+			if pageNum == 1 {
+				return errors.Wrap(err, "search selectors may be broken")
+			}
+			break
+		}
+		urls = append(urls, excelUrls...)
+	}
+	if len(urls) > args.Count {
+		urls = urls[:args.Count]
+	}
+
+	report := models.NewReport(cli.GetRunID(ctx))
+	failed := 0
+	var downloadErr error
+	for i := range urls {
+		cx, err := s.profile.DownloadArticle(ctx, &urls[i])
+		if err != nil {
+			log.With("url", urls[i].Url).Warnf("Doctor download error: %s", err.Error())
+			// pages failing to parse are downloaded articles without fields
+			if models.ErrorClass(err) == models.ReasonParse {
+				cx = &models.Complex{}
+			} else {
+				failed++
+				downloadErr = err
+				continue
+			}
+		}
+		if cx.TooManyRequests {
+			report.TooManyRequests++
+			continue
+		}
+		report.Downloaded++
+		for _, field := range s.selectorFields() {
+			if filled(cx, field) {
+				report.Filled[string(field)]++
+			}
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "Doctor %s: %d of %d articles downloaded, %d failed, %d too many requests\n",
+		args.Profile, report.Downloaded, len(urls), failed, report.TooManyRequests)
+	if report.Downloaded == 0 {
+		if err = tw.Flush(); err != nil {
+			return err
+		}
+		if downloadErr != nil {
+			return errors.Wrap(downloadErr, "no article downloaded")
+		}
+
+		return errors.New("no article downloaded")
+	}
+	_, _ = fmt.Fprintln(tw, "field\tselector\tfilled\trate\tstatus")
+	broken := make([]string, 0)
+	selectors := s.profile.Selectors()
+	for _, field := range s.selectorFields() {
+		rate := report.FillRate(field)
+		status := "ok"
+		switch {
+		case optionalFields[field] && report.Filled[string(field)] == 0:
+			status = "check"
+		case !optionalFields[field] && rate < args.MinFillRate:
+			status = "broken"
+			broken = append(broken, string(field))
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%d/%d\t%.2f\t%s\n", field, selectors[field],
+			report.Filled[string(field)], report.Downloaded, rate, status)
+	}
+	if err = tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(broken) > 0 {
+		return errors.New(fmt.Sprintf("broken selectors: %s", strings.Join(broken, ", ")))
+	}
+
+	return nil
+}
@@ -5,26 +5,44 @@ import (
 	"fmt"
 	"github.com/pkg/errors"
 	"github.com/sku4/mslu-parser/internal/repository"
+	"github.com/sku4/mslu-parser/internal/service/analyzer/language"
 	"github.com/sku4/mslu-parser/internal/service/parser/spiegel"
 	"github.com/sku4/mslu-parser/internal/service/parser/zeit"
 	"github.com/sku4/mslu-parser/models"
 	"github.com/sku4/mslu-parser/models/cli"
 	"github.com/sku4/mslu-parser/pkg/logger"
+	"github.com/sku4/mslu-parser/pkg/metrics"
+	"github.com/sku4/mslu-parser/pkg/normalizer"
 	"hash/crc32"
+	"reflect"
 	"sync"
+	"time"
 )
 
 //go:generate mockgen -source=parser.go -destination=mocks/parser.go
 
+const (
+	languageKeep     = "keep"
+	languageDrop     = "drop"
+	languageSeparate = "separate"
+)
+
 type iProfile interface {
 	Auth(context.Context) error
 	Shutdown() error
 	SearchArticles(ctx context.Context, pageNum int) ([]models.ExcelUrl, error)
 	DownloadArticle(ctx context.Context, excelUrl *models.ExcelUrl) (*models.Complex, error)
+	Selectors() map[models.Field]string
+}
+
+type iAnalyzer interface {
+	Analyze(*models.Complex)
 }
 
 type Service struct {
 	repos                *repository.Repository
+	analyzer             iAnalyzer
+	normalizer           *normalizer.Normalizer
 	profile              iProfile
 	urlsChan             chan models.ExcelUrl
 	complexChan          chan models.Complex
@@ -34,29 +52,82 @@ type Service struct {
 	tooManyRequestsLimit int
 	rwMutex              *sync.RWMutex
 	crcTable             *crc32.Table
+	report               *models.Report
+	reportMutex          *sync.Mutex
+	fillFields           []models.Field
+	abort                context.CancelFunc
+	abortErr             error
+
+	// fillWindow holds filled flags of fillFields of the last FillSample articles, fillCounts their sums
+	fillWindow [][]bool
+	fillCounts []int
 }
 
-func NewService(repos *repository.Repository) *Service {
+func NewService(repos *repository.Repository, analyzer iAnalyzer) *Service {
 	return &Service{
 		repos:        repos,
+		analyzer:     analyzer,
 		completeChan: make(chan struct{}, 1),
 		urlsChan:     make(chan models.ExcelUrl, 10000),
 		complexChan:  make(chan models.Complex, 10000),
 		rwMutex:      &sync.RWMutex{},
 		crcTable:     crc32.MakeTable(crc32.IEEE),
+		report:       models.NewReport(""),
+		reportMutex:  &sync.Mutex{},
+	}
+}
+
+func (s *Service) Run(ctx context.Context) error {
+	return s.run(ctx, s.searchArticles)
+}
+
+// RetryFailed downloads again articles of profile with failure snapshots or dead letters,
+// snapshots and dead letters of articles extracted now are removed
+func (s *Service) RetryFailed(ctx context.Context) error {
+	if s.repos.Failures == nil && s.repos.DeadLetters == nil {
+		return errors.New("failures directory and dead letters not set")
 	}
+
+	return s.run(ctx, s.queueFailures)
 }
 
-func (s *Service) Run(ctx context.Context) (err error) {
+// run downloads and saves articles queued by search
+func (s *Service) run(ctx context.Context, search func(context.Context, *sync.WaitGroup) error) (err error) {
 	args := cli.GetArgs(ctx)
-	switch args.Profile {
-	case "zeit":
-		s.profile = zeit.New()
-	case "spiegel":
-		s.profile = spiegel.New()
+	ctx = logger.WithContext(ctx, "profile", args.Profile)
+	s.report = models.NewReport(cli.GetRunID(ctx))
+	defer func() {
+		s.updateReport(func(r *models.Report) {
+			r.End = time.Now()
+			if err != nil {
+				r.Error = err.Error()
+			}
+		})
+	}()
+	if s.profile, err = newProfile(args.Profile); err != nil {
+		return err
+	}
+	switch args.Language {
+	case "", languageKeep, languageDrop, languageSeparate:
 	default:
-		return errors.New(fmt.Sprintf("Profile '%s' not found", args.Profile))
+		return errors.New(fmt.Sprintf("Language option '%s' not found", args.Language))
+	}
+	steps, err := normalizer.ParseSteps(args.Normalize)
+	if err != nil {
+		return err
 	}
+	s.normalizer = normalizer.New(steps...)
+	if s.fillFields, err = models.ParseFields(args.FillFields); err != nil {
+		return err
+	}
+	for _, field := range s.fillFields {
+		if _, ok := s.profile.Selectors()[field]; !ok {
+			return errors.New(fmt.Sprintf("Field '%s' is not extracted by profile '%s'", field, args.Profile))
+		}
+	}
+	s.fillWindow, s.fillCounts = nil, make([]int, len(s.fillFields))
+	ctx, s.abort = context.WithCancel(ctx)
+	defer s.abort()
 
 	s.urls, err = s.repos.Excel.GetUsedUrls(ctx)
 	if err != nil {
@@ -68,11 +139,28 @@ func (s *Service) Run(ctx context.Context) (err error) {
 	}
 
 	s.tooManyRequestsLimit = 5
-	if err = s.parse(ctx); err != nil {
+	if err = s.parse(ctx, search); err != nil {
 		return err
 	}
 
-	return nil
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	return s.abortErr
+}
+
+// profiles are constructors of crawl profiles by name
+var profiles = map[string]func() iProfile{
+	"zeit":    func() iProfile { return zeit.New() },
+	"spiegel": func() iProfile { return spiegel.New() },
+}
+
+func newProfile(name string) (iProfile, error) {
+	if newFunc, ok := profiles[name]; ok {
+		return newFunc(), nil
+	}
+
+	return nil, errors.New(fmt.Sprintf("Profile '%s' not found", name))
 }
 
 func (s *Service) Shutdown() error {
@@ -94,14 +182,14 @@ func (s *Service) Shutdown() error {
 	return nil
 }
 
-func (s *Service) parse(ctx context.Context) (err error) {
+func (s *Service) parse(ctx context.Context, search func(context.Context, *sync.WaitGroup) error) (err error) {
 	s.isParseRun = true
 	wg := &sync.WaitGroup{}
 
 	// search new articles
 	wg.Add(1)
 	go func() {
-		_ = s.searchArticles(ctx, wg)
+		_ = search(ctx, wg)
 	}()
 
 	// download articles
@@ -129,10 +217,11 @@ func (s *Service) parse(ctx context.Context) (err error) {
 
 func (s *Service) searchArticles(ctx context.Context, wg *sync.WaitGroup) error {
 	defer wg.Done()
+	defer close(s.urlsChan)
 	if s.profile == nil {
 		return models.ProfileNotInitError
 	}
-	log := logger.Get()
+	log := logger.FromContext(ctx)
 	args := cli.GetArgs(ctx)
 
 	pageNum := 1
@@ -140,45 +229,74 @@ func (s *Service) searchArticles(ctx context.Context, wg *sync.WaitGroup) error
 	for {
 		select {
 		case <-ctx.Done():
-			close(s.urlsChan)
-
 			return nil
 		default:
 		}
-
-		s.rwMutex.RLock()
-		if s.tooManyRequestsLimit == 0 {
-
+		if s.tooManyRequests() {
 			return nil
 		}
-		s.rwMutex.RUnlock()
 
 		excelUrls, err := s.profile.SearchArticles(ctx, pageNum)
 		if err != nil {
 			if !errors.Is(err, models.ArticlesNotFoundError) {
 				log.Warnf("Search articles pageNum %d error: %s", pageNum, err.Error())
 			}
-			break
+			return nil
 		}
+		// failed pages are counted by neither the report nor the metrics
+		s.updateReport(func(r *models.Report) {
+			r.Pages++
+		})
+		metrics.SearchedPages.WithLabelValues(args.Profile).Inc()
 		for _, excelUrl := range excelUrls {
 			if countLimit == 0 {
-				close(s.urlsChan)
-
 				return nil
 			}
 			url := crc32.Checksum([]byte(excelUrl.Url), s.crcTable)
 			excelRow, hasUrl := s.urls[url]
 			excelUrl.ExcelRow = excelRow
-			if !hasUrl || args.Update {
-				s.urlsChan <- excelUrl
+			if s.deadLetter(ctx, excelUrl.Url) {
+				s.updateReport(func(r *models.Report) {
+					r.Skipped[models.ReasonDeadLetter]++
+				})
+			} else if !hasUrl || args.Update {
+				if !s.queue(ctx, excelUrl) {
+					return nil
+				}
 				countLimit--
+			} else {
+				s.updateReport(func(r *models.Report) {
+					r.Skipped[models.ReasonExists]++
+				})
 			}
 		}
 		pageNum++
 	}
-	close(s.urlsChan)
+}
 
-	return nil
+// queue sends url to downloaders, false when the run is done before
+func (s *Service) queue(ctx context.Context, excelUrl models.ExcelUrl) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case s.urlsChan <- excelUrl:
+	}
+	args := cli.GetArgs(ctx)
+	metrics.QueuedUrls.WithLabelValues(args.Profile).Inc()
+	metrics.QueueDepth.WithLabelValues("urls").Set(float64(len(s.urlsChan)))
+	s.updateReport(func(r *models.Report) {
+		r.Queued++
+	})
+
+	return true
+}
+
+// tooManyRequests reports whether the limit of 429 responses of the run is reached
+func (s *Service) tooManyRequests() bool {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	return s.tooManyRequestsLimit <= 0
 }
 
 func (s *Service) downloadArticles(ctx context.Context, wg *sync.WaitGroup) error {
@@ -186,50 +304,172 @@ func (s *Service) downloadArticles(ctx context.Context, wg *sync.WaitGroup) erro
 	if s.profile == nil {
 		return models.ProfileNotInitError
 	}
-	log := logger.Get()
+	args := cli.GetArgs(ctx)
 
-	for excelUrl := range s.urlsChan {
+	for {
+		var excelUrl models.ExcelUrl
 		select {
 		case <-ctx.Done():
 			return nil
-		default:
+		case url, ok := <-s.urlsChan:
+			if !ok {
+				return nil
+			}
+			excelUrl = url
 		}
-
-		s.rwMutex.RLock()
-		if s.tooManyRequestsLimit == 0 {
-
+		metrics.QueueDepth.WithLabelValues("urls").Set(float64(len(s.urlsChan)))
+		if s.tooManyRequests() {
 			return nil
 		}
-		s.rwMutex.RUnlock()
 
-		modelComplex, err := s.profile.DownloadArticle(ctx, &excelUrl)
+		urlCtx := logger.WithContext(ctx, "url", excelUrl.Url)
+		log := logger.FromContext(urlCtx)
+		modelComplex, attempts, err := s.download(urlCtx, &excelUrl)
+		// downloads interrupted by cancel of the run are not failures of the url
+		if err != nil && (ctx.Err() != nil || errors.Is(err, context.Canceled)) {
+			return nil
+		}
 		if err != nil {
-			log.Errorf("Download article (%s) error: %s", excelUrl.Url, err.Error())
+			reason := models.ErrorClass(err)
+			metrics.FailedArticles.WithLabelValues(args.Profile, reason).Inc()
+			s.fail(excelUrl.Url, reason, err)
+			log.Errorf("Download article error: %s", err.Error())
+			s.saveSnapshot(urlCtx, err)
+			s.putDeadLetter(urlCtx, excelUrl.Url, attempts, err)
+			// pages failing to parse are downloaded articles without fields, broken selectors end here
+			if reason == models.ReasonParse {
+				s.trackFill(ctx, &models.Complex{})
+			}
 		}
 		if modelComplex != nil && modelComplex.TooManyRequests {
 			s.rwMutex.Lock()
 			s.tooManyRequestsLimit--
 			s.rwMutex.Unlock()
-			log.Errorf("Download article too many requests (%s)", excelUrl.Url)
+			s.updateReport(func(r *models.Report) {
+				r.TooManyRequests++
+			})
+			s.fail(excelUrl.Url, models.ReasonTooManyRequests, errors.New("too many requests"))
+			log.Error("Download article too many requests")
 		} else if err == nil {
+			modelComplex.Profile = args.Profile
+			s.normalize(modelComplex)
+			metrics.DownloadedArticles.WithLabelValues(args.Profile).Inc()
+			s.trackFill(ctx, modelComplex)
+			s.removeSnapshot(urlCtx, excelUrl.Url)
+			s.removeDeadLetter(urlCtx, excelUrl.Url)
 			s.complexChan <- *modelComplex
+			metrics.QueueDepth.WithLabelValues("complex").Set(float64(len(s.complexChan)))
 		}
 	}
+}
 
-	return nil
+// download downloads article, temporary errors are retried with backoff doubled per attempt
+func (s *Service) download(ctx context.Context, excelUrl *models.ExcelUrl) (*models.Complex, int, error) {
+	args := cli.GetArgs(ctx)
+	backoff := args.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		modelComplex, err := s.profile.DownloadArticle(ctx, excelUrl)
+		var downloadErr *models.DownloadError
+		if err == nil || attempt > args.Retries || !errors.As(err, &downloadErr) || !downloadErr.Retryable() {
+			return modelComplex, attempt, err
+		}
+
+		logger.FromContext(ctx).Warnf("Download article attempt %d error: %s, retry in %s",
+			attempt, err.Error(), backoff)
+		s.updateReport(func(r *models.Report) {
+			r.Retried++
+		})
+		select {
+		case <-ctx.Done():
+			return nil, attempt, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
 }
 
 func (s *Service) saveArticles(ctx context.Context, wgs *sync.WaitGroup) error {
 	defer wgs.Done()
-	log := logger.Get()
+	log := logger.FromContext(ctx)
+	args := cli.GetArgs(ctx)
 
 	for cx := range s.complexChan {
+		metrics.QueueDepth.WithLabelValues("complex").Set(float64(len(s.complexChan)))
+		s.analyzer.Analyze(&cx)
+		if lang := foreignLanguage(cx); lang != "" {
+			switch args.Language {
+			case languageDrop:
+				s.updateReport(func(r *models.Report) {
+					r.Skipped[models.ReasonLanguage]++
+				})
+				log.With("url", cx.Url).Infof("Drop article in language '%s'", lang)
+				continue
+			case languageSeparate:
+				cx.Profile = separatedProfile(args.Profile, lang)
+			}
+		}
 		err := s.repos.Excel.SetComplex(ctx, cx)
 		if err != nil {
-			log.Errorf("Save articles error: %s", err.Error())
+			s.fail(cx.Url, models.ReasonSave, err)
+			log.With("url", cx.Url).Errorf("Save articles error: %s", err.Error())
 			return errors.Wrap(err, "Save articles")
 		}
+		metrics.SavedArticles.WithLabelValues(args.Profile).Inc()
+		s.updateReport(func(r *models.Report) {
+			r.SavedUrls = append(r.SavedUrls, cx.Url)
+			if cx.ExcelRow != nil {
+				r.Updated++
+			} else {
+				r.New++
+			}
+		})
 	}
 
 	return nil
 }
+
+// separatedProfile returns profile of articles in lang kept apart from german ones of profile,
+// storages, export, stats and corpus treat it as a profile of its own
+func separatedProfile(profile, lang string) string {
+	return profile + "_" + lang
+}
+
+// foreignLanguage returns detected language of non-german article, empty for german or undetermined
+func foreignLanguage(cx models.Complex) string {
+	if cx.Analysis.Language == nil || cx.Analysis.Language.Article == language.German {
+		return ""
+	}
+
+	return cx.Analysis.Language.Article
+}
+
+// normalize replaces extracted values of complex with normalized ones, raw values are kept when changed
+func (s *Service) normalize(cx *models.Complex) {
+	if s.normalizer == nil {
+		return
+	}
+	raw := &models.Raw{
+		Title:       cx.Title,
+		OverTitle:   cx.OverTitle,
+		Lead:        cx.Lead,
+		Subtitles:   cx.Subtitles,
+		ImageTitles: cx.ImageTitles,
+		Author:      cx.Author,
+	}
+	normalized := models.Raw{
+		Title:       s.normalizer.String(cx.Title),
+		OverTitle:   s.normalizer.String(cx.OverTitle),
+		Lead:        s.normalizer.String(cx.Lead),
+		Subtitles:   s.normalizer.Strings(cx.Subtitles),
+		ImageTitles: s.normalizer.Strings(cx.ImageTitles),
+		Author:      s.normalizer.String(cx.Author),
+	}
+	if reflect.DeepEqual(*raw, normalized) {
+		return
+	}
+
+	cx.Title, cx.OverTitle, cx.Lead = normalized.Title, normalized.OverTitle, normalized.Lead
+	cx.Subtitles, cx.ImageTitles = normalized.Subtitles, normalized.ImageTitles
+	cx.Author = normalized.Author
+	cx.Raw = raw
+}
@@ -0,0 +1,432 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/sku4/mslu-parser/internal/repository"
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/models/cli"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeExcel struct {
+	repository.Excel
+	mutex sync.Mutex
+	saved []models.Complex
+}
+
+func (e *fakeExcel) GetUsedUrls(context.Context) (map[uint32]*models.ExcelRow, error) {
+	return map[uint32]*models.ExcelRow{}, nil
+}
+
+func (e *fakeExcel) SetComplex(_ context.Context, modelComplex models.Complex) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.saved = append(e.saved, modelComplex)
+
+	return nil
+}
+
+func (e *fakeExcel) Close() error {
+	return nil
+}
+
+type nopAnalyzer struct{}
+
+func (nopAnalyzer) Analyze(*models.Complex) {}
+
+// langAnalyzer detects lang in every article
+type langAnalyzer struct {
+	lang string
+}
+
+func (a langAnalyzer) Analyze(cx *models.Complex) {
+	cx.Analysis.Language = &models.Language{Article: a.lang}
+}
+
+// fakeProfile finds pages of 50 urls and answers downloads with download
+type fakeProfile struct {
+	download func(ctx context.Context) (*models.Complex, error)
+	// pages found before search fails with searchErr, all pages are found if 0
+	pages     int
+	searchErr error
+}
+
+func (p *fakeProfile) Auth(context.Context) error {
+	return nil
+}
+
+func (p *fakeProfile) Shutdown() error {
+	return nil
+}
+
+func (p *fakeProfile) SearchArticles(_ context.Context, pageNum int) ([]models.ExcelUrl, error) {
+	if p.pages > 0 && pageNum > p.pages {
+		return nil, p.searchErr
+	}
+	urls := make([]models.ExcelUrl, 50)
+	for i := range urls {
+		urls[i].Url = fmt.Sprintf("https://example.com/%d/%d", pageNum, i)
+	}
+
+	return urls, nil
+}
+
+func (p *fakeProfile) DownloadArticle(ctx context.Context, excelUrl *models.ExcelUrl) (*models.Complex, error) {
+	cx, err := p.download(ctx)
+	if cx != nil {
+		cx.ExcelUrl = *excelUrl
+	}
+
+	return cx, err
+}
+
+func (p *fakeProfile) Selectors() map[models.Field]string {
+	return map[models.Field]string{models.FieldTitle: "h1"}
+}
+
+func TestRunStops(t *testing.T) {
+	tests := []struct {
+		name     string
+		download func(ctx context.Context) (*models.Complex, error)
+		cancel   bool
+	}{
+		{
+			name: "too many requests",
+			download: func(context.Context) (*models.Complex, error) {
+				return &models.Complex{TooManyRequests: true}, nil
+			},
+		},
+		{
+			name: "too many requests canceled",
+			download: func(ctx context.Context) (*models.Complex, error) {
+				select {
+				case <-ctx.Done():
+				case <-time.After(10 * time.Millisecond):
+				}
+				return &models.Complex{TooManyRequests: true}, nil
+			},
+			cancel: true,
+		},
+		{
+			name: "hanging download canceled",
+			download: func(ctx context.Context) (*models.Complex, error) {
+				<-ctx.Done()
+				return nil, &models.DownloadError{Class: models.ReasonNetwork, Err: ctx.Err()}
+			},
+			cancel: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profiles["fake"] = func() iProfile {
+				return &fakeProfile{download: tt.download}
+			}
+			defer delete(profiles, "fake")
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctx = cli.SetArgs(ctx, cli.Arguments{
+				Profile:    "fake",
+				Count:      1000,
+				Normalize:  "none",
+				FillFields: "title",
+			})
+			s := NewService(&repository.Repository{Excel: &fakeExcel{}}, nopAnalyzer{})
+
+			done := make(chan error, 1)
+			go func() {
+				err := s.Run(ctx)
+				if shutdownErr := s.Shutdown(); err == nil {
+					err = shutdownErr
+				}
+				done <- err
+			}()
+			if tt.cancel {
+				time.Sleep(20 * time.Millisecond)
+				cancel()
+			}
+
+			select {
+			case err := <-done:
+				if err != nil {
+					t.Fatalf("Run() error = %v", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Run() did not return")
+			}
+		})
+	}
+}
+
+type fakeDeadLetters struct {
+	repository.DeadLetters
+	mutex   sync.Mutex
+	put     int
+	letters []models.DeadLetter
+	removed []string
+}
+
+func (d *fakeDeadLetters) Get(context.Context, string) (*models.DeadLetter, error) {
+	return nil, nil
+}
+
+func (d *fakeDeadLetters) Put(context.Context, models.DeadLetter) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.put++
+
+	return nil
+}
+
+func (d *fakeDeadLetters) Remove(_ context.Context, url string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.removed = append(d.removed, url)
+
+	return nil
+}
+
+func (d *fakeDeadLetters) List(context.Context, string) ([]models.DeadLetter, error) {
+	return d.letters, nil
+}
+
+func TestRunCanceledNotFailed(t *testing.T) {
+	profiles["fake"] = func() iProfile {
+		return &fakeProfile{download: func(ctx context.Context) (*models.Complex, error) {
+			<-ctx.Done()
+			return nil, &models.DownloadError{Class: models.ReasonNetwork, Err: ctx.Err()}
+		}}
+	}
+	defer delete(profiles, "fake")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = cli.SetArgs(ctx, cli.Arguments{
+		Profile:    "fake",
+		Count:      20,
+		Normalize:  "none",
+		FillFields: "title",
+	})
+	deadLetters := &fakeDeadLetters{}
+	s := NewService(&repository.Repository{Excel: &fakeExcel{}, DeadLetters: deadLetters}, nopAnalyzer{})
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if err := s.Run(ctx); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	_ = s.Shutdown()
+
+	report := s.Report()
+	if len(report.Failed) != 0 || len(report.FailedUrls) != 0 {
+		t.Errorf("report failed = %v, want none", report.Failed)
+	}
+	if deadLetters.put != 0 {
+		t.Errorf("dead letters put = %d, want 0", deadLetters.put)
+	}
+}
+
+func TestDoctorCount(t *testing.T) {
+	profiles["fake"] = func() iProfile {
+		return &fakeProfile{download: func(context.Context) (*models.Complex, error) {
+			return &models.Complex{Title: "Titel"}, nil
+		}}
+	}
+	defer delete(profiles, "fake")
+
+	for _, count := range []int{0, -1} {
+		ctx := cli.SetArgs(context.Background(), cli.Arguments{Profile: "fake", Count: count})
+		s := NewService(&repository.Repository{}, nopAnalyzer{})
+		if err := s.Doctor(ctx, io.Discard); err == nil {
+			t.Errorf("Doctor() count %d error = nil, want error", count)
+		}
+	}
+}
+
+func TestRunFillRateCollapse(t *testing.T) {
+	tests := []struct {
+		name  string
+		ok    int
+		abort bool
+	}{
+		{"selectors break halfway", 30, true},
+		{"selectors keep working", 100, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mutex, downloads := sync.Mutex{}, 0
+			profiles["fake"] = func() iProfile {
+				return &fakeProfile{download: func(context.Context) (*models.Complex, error) {
+					mutex.Lock()
+					defer mutex.Unlock()
+					downloads++
+					if downloads > tt.ok {
+						return nil, &models.DownloadError{Class: models.ReasonParse, Err: models.ArticleNotFoundError}
+					}
+					return &models.Complex{Title: "Titel"}, nil
+				}}
+			}
+			defer delete(profiles, "fake")
+
+			ctx := cli.SetArgs(context.Background(), cli.Arguments{
+				Profile:     "fake",
+				Count:       100,
+				Normalize:   "none",
+				FillFields:  "title",
+				MinFillRate: 0.1,
+				FillSample:  20,
+			})
+			s := NewService(&repository.Repository{Excel: &fakeExcel{}}, nopAnalyzer{})
+			err := s.Run(ctx)
+			_ = s.Shutdown()
+			if (err != nil) != tt.abort {
+				t.Errorf("Run() error = %v, want abort %v", err, tt.abort)
+			}
+		})
+	}
+}
+
+func TestDoctorDownloadFailed(t *testing.T) {
+	profiles["fake"] = func() iProfile {
+		return &fakeProfile{download: func(context.Context) (*models.Complex, error) {
+			return nil, &models.DownloadError{Class: models.ReasonNetwork, Err: errors.New("connection refused")}
+		}}
+	}
+	defer delete(profiles, "fake")
+
+	ctx := cli.SetArgs(context.Background(), cli.Arguments{Profile: "fake", Count: 5, MinFillRate: 0.1})
+	s := NewService(&repository.Repository{}, nopAnalyzer{})
+	out := &strings.Builder{}
+	err := s.Doctor(ctx, out)
+	if err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("Doctor() error = %v, want download error", err)
+	}
+	if strings.Contains(out.String(), "broken") {
+		t.Errorf("Doctor() output reports broken selectors:\n%s", out.String())
+	}
+}
+
+func TestRunLanguage(t *testing.T) {
+	tests := []struct {
+		language string
+		lang     string
+		want     []string
+	}{
+		{languageKeep, "en", []string{"fake"}},
+		{languageDrop, "en", nil},
+		{languageSeparate, "en", []string{"fake_en"}},
+		{languageSeparate, "de", []string{"fake"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language+" "+tt.lang, func(t *testing.T) {
+			profiles["fake"] = func() iProfile {
+				return &fakeProfile{download: func(context.Context) (*models.Complex, error) {
+					return &models.Complex{Title: "Titel"}, nil
+				}}
+			}
+			defer delete(profiles, "fake")
+
+			ctx := cli.SetArgs(context.Background(), cli.Arguments{
+				Profile:    "fake",
+				Count:      1,
+				Normalize:  "none",
+				FillFields: "title",
+				Language:   tt.language,
+			})
+			excel := &fakeExcel{}
+			s := NewService(&repository.Repository{Excel: excel}, langAnalyzer{lang: tt.lang})
+			if err := s.Run(ctx); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			_ = s.Shutdown()
+
+			var got []string
+			for _, cx := range excel.saved {
+				got = append(got, cx.Profile)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("saved profiles = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunPages(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"last page", models.ArticlesNotFoundError},
+		{"failed page", errors.New("status 500")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profiles["fake"] = func() iProfile {
+				return &fakeProfile{
+					download: func(context.Context) (*models.Complex, error) {
+						return &models.Complex{Title: "Titel"}, nil
+					},
+					pages:     2,
+					searchErr: tt.err,
+				}
+			}
+			defer delete(profiles, "fake")
+
+			ctx := cli.SetArgs(context.Background(), cli.Arguments{
+				Profile:    "fake",
+				Count:      1000,
+				Normalize:  "none",
+				FillFields: "title",
+			})
+			s := NewService(&repository.Repository{Excel: &fakeExcel{}}, nopAnalyzer{})
+			if err := s.Run(ctx); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			_ = s.Shutdown()
+
+			// like the searched pages metric, the report counts found pages only
+			if got := s.Report().Pages; got != 2 {
+				t.Errorf("report pages = %d, want 2", got)
+			}
+		})
+	}
+}
+
+func TestRetryFailedDeadLetters(t *testing.T) {
+	profiles["fake"] = func() iProfile {
+		return &fakeProfile{download: func(context.Context) (*models.Complex, error) {
+			return &models.Complex{Title: "Titel"}, nil
+		}}
+	}
+	defer delete(profiles, "fake")
+
+	ctx := cli.SetArgs(context.Background(), cli.Arguments{
+		Profile:    "fake",
+		Normalize:  "none",
+		FillFields: "title",
+	})
+	deadLetters := &fakeDeadLetters{letters: []models.DeadLetter{
+		{Url: "https://example.com/404", Profile: "fake", Reason: models.ReasonClient},
+	}}
+	excel := &fakeExcel{}
+	s := NewService(&repository.Repository{Excel: excel, DeadLetters: deadLetters}, nopAnalyzer{})
+	if err := s.RetryFailed(ctx); err != nil {
+		t.Fatalf("RetryFailed() error = %v", err)
+	}
+	_ = s.Shutdown()
+
+	if len(excel.saved) != 1 || excel.saved[0].Url != "https://example.com/404" {
+		t.Errorf("saved = %v, want the dead letter url", excel.saved)
+	}
+	if !reflect.DeepEqual(deadLetters.removed, []string{"https://example.com/404"}) {
+		t.Errorf("removed dead letters = %v, want the retried url", deadLetters.removed)
+	}
+}
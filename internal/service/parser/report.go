@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"github.com/sku4/mslu-parser/models"
+)
+
+// Report returns copy of the report of the last run
+func (s *Service) Report() *models.Report {
+	s.reportMutex.Lock()
+	defer s.reportMutex.Unlock()
+
+	report := *s.report
+	report.Arguments = make(map[string]string, len(s.report.Arguments))
+	for name, value := range s.report.Arguments {
+		report.Arguments[name] = value
+	}
+	report.Skipped = copyCounts(s.report.Skipped)
+	report.Failed = copyCounts(s.report.Failed)
+	report.Filled = copyCounts(s.report.Filled)
+	report.FailedUrls = append([]models.FailedUrl{}, s.report.FailedUrls...)
+	report.SavedUrls = append([]string{}, s.report.SavedUrls...)
+
+	return &report
+}
+
+func (s *Service) updateReport(update func(r *models.Report)) {
+	s.reportMutex.Lock()
+	defer s.reportMutex.Unlock()
+
+	update(s.report)
+}
+
+// fail counts failed article by reason and keeps its url
+func (s *Service) fail(url, reason string, err error) {
+	s.updateReport(func(r *models.Report) {
+		r.Failed[reason]++
+		r.FailedUrls = append(r.FailedUrls, models.FailedUrl{
+			Url:    url,
+			Reason: reason,
+			Error:  err.Error(),
+		})
+	})
+}
+
+func copyCounts(counts map[string]int) map[string]int {
+	c := make(map[string]int, len(counts))
+	for reason, count := range counts {
+		c[reason] = count
+	}
+
+	return c
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/sku4/mslu-parser/models/cli"
 	"github.com/sku4/mslu-parser/models/spiegel"
 	"github.com/sku4/mslu-parser/pkg/logger"
+	"github.com/sku4/mslu-parser/pkg/metrics"
+	"go.uber.org/zap"
 	"io"
 	"mime/multipart"
 	"net/http"
@@ -19,12 +21,29 @@ import (
 	"time"
 )
 
+// selectors of article fields, date and author are read from content attribute
+var selectors = map[models.Field]string{
+	models.FieldTitle:       "main article header h2 .align-middle",
+	models.FieldOverTitle:   "main article header h2 .text-primary-base",
+	models.FieldLead:        "main article header .leading-loose",
+	models.FieldSubtitles:   "main article section h3",
+	models.FieldImageTitles: "main article figcaption p",
+	models.FieldDate:        `meta[name="date"]`,
+	models.FieldAuthor:      `meta[name="author"]`,
+}
+
+// paywallSelector matches SPIEGEL+ gate of articles without access
+const paywallSelector = `[data-area="paywall"], [data-contains-flags~="Spplus-paid"]`
+
 type Spiegel struct {
 	authCookie []*http.Cookie
+	log        *zap.SugaredLogger
 }
 
 func New() *Spiegel {
-	return &Spiegel{}
+	return &Spiegel{
+		log: logger.Get(),
+	}
 }
 
 const (
@@ -33,17 +52,20 @@ const (
 	authUrlHost = "https://gruppenkonto.spiegel.de"
 	targetUrl   = "https://www.spiegel.de"
 	cookieAuth  = "accessInfo"
+	// requestTimeout bounds requests of a stalled server, cancel of the run stops them earlier
+	requestTimeout = time.Minute
 )
 
 func (s *Spiegel) Auth(ctx context.Context) error {
+	s.log = logger.FromContext(ctx)
 	s.authCookie = make([]*http.Cookie, 0, 10)
 	args := cli.GetArgs(ctx)
 	if args.Login == "" || args.Password == "" {
 		return errors.New("login or password not set")
 	}
 
-	var client http.Client
-	reqCsrf, err := http.NewRequest(http.MethodGet, authUrl, bytes.NewBuffer([]byte{}))
+	client := http.Client{Transport: metrics.Transport, Timeout: requestTimeout}
+	reqCsrf, err := http.NewRequestWithContext(ctx, http.MethodGet, authUrl, bytes.NewBuffer([]byte{}))
 	if err != nil {
 		return errors.New(fmt.Sprintf("error create request: %s", err.Error()))
 	}
@@ -84,7 +106,7 @@ func (s *Spiegel) Auth(ctx context.Context) error {
 	_ = w.WriteField("javax.faces.ViewState", "stateless")
 	_ = w.Close()
 
-	reqAuth, err := http.NewRequest(http.MethodPost, authUrl, &b)
+	reqAuth, err := http.NewRequestWithContext(ctx, http.MethodPost, authUrl, &b)
 	if err != nil {
 		return errors.New(fmt.Sprintf("error create request: %s", err.Error()))
 	}
@@ -119,9 +141,13 @@ func (s *Spiegel) Auth(ctx context.Context) error {
 	return nil
 }
 
+// Selectors returns selectors of article fields
+func (s *Spiegel) Selectors() map[models.Field]string {
+	return selectors
+}
+
 func (s *Spiegel) Shutdown() error {
-	log := logger.Get()
-	log.Info("Saving articles to excel")
+	s.log.Info("Saving articles to excel")
 
 	return nil
 }
@@ -134,7 +160,7 @@ func (s *Spiegel) SearchArticles(ctx context.Context, pageNum int) ([]models.Exc
 		args.SpiegelSuchbegriff, after.Unix(), before.Unix(), pageNum)
 	resp, err := s.request(ctx, searchArticlesUrl)
 	if err != nil {
-		return nil, errors.Wrap(err, fmt.Sprintf("status code %d", resp.StatusCode))
+		return nil, errors.Wrap(err, "search articles")
 	}
 	defer func() {
 		_ = resp.Body.Close()
@@ -169,7 +195,7 @@ func (s *Spiegel) SearchArticles(ctx context.Context, pageNum int) ([]models.Exc
 func (s *Spiegel) DownloadArticle(ctx context.Context, excelUrl *models.ExcelUrl) (*models.Complex, error) {
 	resp, err := s.request(ctx, excelUrl.Url)
 	if err != nil {
-		return nil, errors.Wrap(err, fmt.Sprintf("status code %d", resp.StatusCode))
+		return nil, err
 	}
 
 	defer func() {
@@ -184,30 +210,54 @@ func (s *Spiegel) DownloadArticle(ctx context.Context, excelUrl *models.ExcelUrl
 
 		return modelComplex, nil
 	}
+	if err = models.StatusError(resp.StatusCode); err != nil {
+		return nil, err
+	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "article read all")
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
 	if err != nil {
 		return nil, errors.Wrap(err, "create document reader")
 	}
 
 	subtitles := make([]string, 0)
 	imageTitles := make([]string, 0)
-	title := doc.Find("main article header h2 .align-middle").Text()
-	overTitle := doc.Find("main article header h2 .text-primary-base").Text()
-	lead := doc.Find("main article header .leading-loose").Text()
-	doc.Find("main article section h3").Each(func(i int, s *goquery.Selection) {
+	title := doc.Find(selectors[models.FieldTitle]).Text()
+	overTitle := doc.Find(selectors[models.FieldOverTitle]).Text()
+	date, _ := time.Parse(time.RFC3339, doc.Find(selectors[models.FieldDate]).AttrOr("content", ""))
+	author := doc.Find(selectors[models.FieldAuthor]).AttrOr("content", "")
+	lead := doc.Find(selectors[models.FieldLead]).Text()
+	doc.Find(selectors[models.FieldSubtitles]).Each(func(i int, s *goquery.Selection) {
 		if strings.TrimSpace(s.Text()) != "" {
 			subtitles = append(subtitles, strings.TrimSpace(s.Text()))
 		}
 	})
-	doc.Find("main article figcaption p").Each(func(i int, s *goquery.Selection) {
+	doc.Find(selectors[models.FieldImageTitles]).Each(func(i int, s *goquery.Selection) {
 		if strings.TrimSpace(s.Text()) != "" {
 			imageTitles = append(imageTitles, strings.TrimSpace(s.Text()))
 		}
 	})
 
 	if title == "" {
-		return nil, models.ArticleNotFoundError
+		class := models.ReasonParse
+		if doc.Find(paywallSelector).Length() > 0 {
+			class = models.ReasonPaywall
+		}
+		return nil, &models.DownloadError{
+			Class: class,
+			Err: &models.SnapshotError{
+				Err: models.ArticleNotFoundError,
+				Snapshot: &models.Snapshot{
+					Url:    excelUrl.Url,
+					Status: resp.StatusCode,
+					Header: resp.Header,
+					Html:   body,
+				},
+			},
+		}
 	}
 
 	modelComplex.Title = strings.TrimSpace(title)
@@ -215,13 +265,15 @@ func (s *Spiegel) DownloadArticle(ctx context.Context, excelUrl *models.ExcelUrl
 	modelComplex.Lead = strings.TrimSpace(lead)
 	modelComplex.Subtitles = subtitles
 	modelComplex.ImageTitles = imageTitles
+	modelComplex.Date = date
+	modelComplex.Author = strings.TrimSpace(author)
 
 	return modelComplex, nil
 }
 
 func (s *Spiegel) request(ctx context.Context, url string) (*http.Response, error) {
-	var client http.Client
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	client := http.Client{Transport: metrics.Transport, Timeout: requestTimeout}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, errors.New(fmt.Sprintf("error create request: %s", err.Error()))
 	}
@@ -231,8 +283,12 @@ func (s *Spiegel) request(ctx context.Context, url string) (*http.Response, erro
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, errors.New(fmt.Sprintf("error request body page: %s", err.Error()))
+		return nil, &models.DownloadError{
+			Class: models.ReasonNetwork,
+			Err:   errors.New(fmt.Sprintf("error request body page: %s", err.Error())),
+		}
 	}
+	logger.FromContext(ctx).Debugf("Request %s status %d", url, resp.StatusCode)
 
 	return resp, nil
 }
@@ -9,17 +9,38 @@ import (
 	"github.com/sku4/mslu-parser/models"
 	"github.com/sku4/mslu-parser/models/cli"
 	"github.com/sku4/mslu-parser/pkg/logger"
+	"github.com/sku4/mslu-parser/pkg/metrics"
+	"go.uber.org/zap"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"strings"
+	"time"
 )
 
+// selectors of article fields, date and author are read from content attribute
+var selectors = map[models.Field]string{
+	models.FieldTitle:       ".article-header h1 .article-heading__title",
+	models.FieldOverTitle:   ".article-header .article-heading__kicker",
+	models.FieldLead:        ".article-header .summary",
+	models.FieldSubtitles:   "h2.article__subheading",
+	models.FieldImageTitles: "figcaption .figure__text",
+	models.FieldDate:        `meta[name="date"]`,
+	models.FieldAuthor:      `meta[name="author"]`,
+}
+
+// paywallSelector matches Z+ gate of articles without access
+const paywallSelector = `.paywall, .gate, .zplus-badge`
+
 type Zeit struct {
 	authCookie []*http.Cookie
+	log        *zap.SugaredLogger
 }
 
 func New() *Zeit {
-	return &Zeit{}
+	return &Zeit{
+		log: logger.Get(),
+	}
 }
 
 const (
@@ -27,20 +48,23 @@ const (
 	authUrl          = "https://meine.zeit.de/anmelden"
 	authUrlHost      = "meine.zeit.de"
 	cookieAuthPrefix = "zeit_sso_"
+	// requestTimeout bounds requests of a stalled server, cancel of the run stops them earlier
+	requestTimeout = time.Minute
 )
 
 func (z *Zeit) Auth(ctx context.Context) error {
+	z.log = logger.FromContext(ctx)
 	z.authCookie = make([]*http.Cookie, 0, 4)
 	args := cli.GetArgs(ctx)
 	if args.Login == "" || args.Password == "" {
 		return errors.New("login or password not set")
 	}
 
-	reqCsrf, err := http.NewRequest(http.MethodGet, authUrl, bytes.NewBuffer([]byte{}))
+	reqCsrf, err := http.NewRequestWithContext(ctx, http.MethodGet, authUrl, bytes.NewBuffer([]byte{}))
 	if err != nil {
 		return errors.New(fmt.Sprintf("error create request: %s", err.Error()))
 	}
-	var client http.Client
+	client := http.Client{Transport: metrics.Transport, Timeout: requestTimeout}
 	respCsrf, err := client.Do(reqCsrf)
 	if err != nil {
 		return errors.New(fmt.Sprintf("error request csrf page: %s", err.Error()))
@@ -73,7 +97,7 @@ func (z *Zeit) Auth(ctx context.Context) error {
 	_ = w.WriteField("csrf_token", csrfToken)
 	_ = w.Close()
 
-	reqAuth, err := http.NewRequest(http.MethodPost, authUrl, &b)
+	reqAuth, err := http.NewRequestWithContext(ctx, http.MethodPost, authUrl, &b)
 	if err != nil {
 		return errors.New(fmt.Sprintf("error create request: %s", err.Error()))
 	}
@@ -123,9 +147,13 @@ func (z *Zeit) Auth(ctx context.Context) error {
 	return nil
 }
 
+// Selectors returns selectors of article fields
+func (z *Zeit) Selectors() map[models.Field]string {
+	return selectors
+}
+
 func (z *Zeit) Shutdown() error {
-	log := logger.Get()
-	log.Info("Saving articles to excel")
+	z.log.Info("Saving articles to excel")
 
 	return nil
 }
@@ -135,7 +163,7 @@ func (z *Zeit) SearchArticles(ctx context.Context, pageNum int) ([]models.ExcelU
 	url := fmt.Sprintf(searchUrl, args.ZeitMode, args.ZeitType, pageNum)
 	resp, err := z.request(ctx, url)
 	if err != nil {
-		return nil, errors.Wrap(err, fmt.Sprintf("status code %d", resp.StatusCode))
+		return nil, errors.Wrap(err, "search articles")
 	}
 
 	defer func() {
@@ -172,7 +200,7 @@ func (z *Zeit) SearchArticles(ctx context.Context, pageNum int) ([]models.ExcelU
 func (z *Zeit) DownloadArticle(ctx context.Context, excelUrl *models.ExcelUrl) (*models.Complex, error) {
 	resp, err := z.request(ctx, excelUrl.Url)
 	if err != nil {
-		return nil, errors.Wrap(err, fmt.Sprintf("status code %d", resp.StatusCode))
+		return nil, err
 	}
 
 	defer func() {
@@ -187,30 +215,54 @@ func (z *Zeit) DownloadArticle(ctx context.Context, excelUrl *models.ExcelUrl) (
 
 		return modelComplex, nil
 	}
+	if err = models.StatusError(resp.StatusCode); err != nil {
+		return nil, err
+	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "article read all")
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
 	if err != nil {
 		return nil, errors.Wrap(err, "create document reader")
 	}
 
 	subtitles := make([]string, 0)
 	imageTitles := make([]string, 0)
-	title := doc.Find(".article-header h1 .article-heading__title").Text()
-	overTitle := doc.Find(".article-header .article-heading__kicker").Text()
-	lead := doc.Find(".article-header .summary").Text()
-	doc.Find("h2.article__subheading").Each(func(i int, s *goquery.Selection) {
+	title := doc.Find(selectors[models.FieldTitle]).Text()
+	overTitle := doc.Find(selectors[models.FieldOverTitle]).Text()
+	date, _ := time.Parse(time.RFC3339, doc.Find(selectors[models.FieldDate]).AttrOr("content", ""))
+	author := doc.Find(selectors[models.FieldAuthor]).AttrOr("content", "")
+	lead := doc.Find(selectors[models.FieldLead]).Text()
+	doc.Find(selectors[models.FieldSubtitles]).Each(func(i int, s *goquery.Selection) {
 		if strings.TrimSpace(s.Text()) != "" {
 			subtitles = append(subtitles, strings.TrimSpace(s.Text()))
 		}
 	})
-	doc.Find("figcaption .figure__text").Each(func(i int, s *goquery.Selection) {
+	doc.Find(selectors[models.FieldImageTitles]).Each(func(i int, s *goquery.Selection) {
 		if strings.TrimSpace(s.Text()) != "" {
 			imageTitles = append(imageTitles, strings.TrimSpace(s.Text()))
 		}
 	})
 
 	if title == "" {
-		return nil, models.ArticleNotFoundError
+		class := models.ReasonParse
+		if doc.Find(paywallSelector).Length() > 0 {
+			class = models.ReasonPaywall
+		}
+		return nil, &models.DownloadError{
+			Class: class,
+			Err: &models.SnapshotError{
+				Err: models.ArticleNotFoundError,
+				Snapshot: &models.Snapshot{
+					Url:    excelUrl.Url,
+					Status: resp.StatusCode,
+					Header: resp.Header,
+					Html:   body,
+				},
+			},
+		}
 	}
 
 	modelComplex.Title = strings.TrimSpace(title)
@@ -218,13 +270,15 @@ func (z *Zeit) DownloadArticle(ctx context.Context, excelUrl *models.ExcelUrl) (
 	modelComplex.Lead = strings.TrimSpace(lead)
 	modelComplex.Subtitles = subtitles
 	modelComplex.ImageTitles = imageTitles
+	modelComplex.Date = date
+	modelComplex.Author = strings.TrimSpace(author)
 
 	return modelComplex, nil
 }
 
 func (z *Zeit) request(ctx context.Context, url string) (*http.Response, error) {
-	var client http.Client
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	client := http.Client{Transport: metrics.Transport, Timeout: requestTimeout}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, errors.New(fmt.Sprintf("error create request: %s", err.Error()))
 	}
@@ -234,8 +288,12 @@ func (z *Zeit) request(ctx context.Context, url string) (*http.Response, error)
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, errors.New(fmt.Sprintf("error request body page: %s", err.Error()))
+		return nil, &models.DownloadError{
+			Class: models.ReasonNetwork,
+			Err:   errors.New(fmt.Sprintf("error request body page: %s", err.Error())),
+		}
 	}
+	logger.FromContext(ctx).Debugf("Request %s status %d", url, resp.StatusCode)
 
 	return resp, nil
 }
@@ -0,0 +1,239 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/sku4/mslu-parser/internal/repository"
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/models/cli"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type Service struct {
+	repos *repository.Repository
+}
+
+func NewService(repos *repository.Repository) *Service {
+	return &Service{
+		repos: repos,
+	}
+}
+
+// Query searches articles and writes keyword-in-context concordance
+func (s *Service) Query(ctx context.Context, w io.Writer) error {
+	args := cli.GetQueryArgs(ctx)
+	if strings.TrimSpace(args.Pattern) == "" {
+		return errors.New("query pattern not set")
+	}
+
+	m, err := compile(args.Mode, args.Pattern)
+	if err != nil {
+		return err
+	}
+
+	fields, err := models.ParseFields(args.Fields)
+	if err != nil {
+		return err
+	}
+
+	filter, err := models.NewFilter(args.Profile, args.From, args.To)
+	if err != nil {
+		return err
+	}
+
+	var complexes []models.Complex
+	if args.Mode == "regex" {
+		complexes, err = s.repos.Excel.GetComplexes(ctx)
+	} else {
+		// narrow down with the repository index before exact matching
+		complexes, err = s.repos.Excel.Search(ctx, args.Pattern, fields)
+	}
+	if err != nil {
+		return err
+	}
+
+	lines := make([]models.Concordance, 0)
+	for _, cx := range filter.Apply(complexes) {
+		lines = append(lines, concordance(cx, m, fields, args.Context)...)
+	}
+	sortLines(lines, args.Sort)
+
+	switch args.Format {
+	case "text":
+		return writeText(w, lines)
+	case "csv":
+		return writeCsv(w, lines)
+	case "html":
+		return writeHtml(w, args.Pattern, lines)
+	}
+
+	return errors.New(fmt.Sprintf("Format '%s' not found", args.Format))
+}
+
+// matcher finds keywords, word and phrase keywords must not be part of a longer word
+type matcher struct {
+	re      *regexp.Regexp
+	bounded bool
+}
+
+// compile builds case-insensitive matcher of mode, word boundaries are checked around matches
+// to not consume neighbouring characters of adjacent keywords
+func compile(mode, pattern string) (matcher, error) {
+	var expr string
+	switch mode {
+	case "word":
+		expr = regexp.QuoteMeta(pattern)
+	case "phrase":
+		words := strings.Fields(pattern)
+		for i, word := range words {
+			words[i] = regexp.QuoteMeta(word)
+		}
+		expr = strings.Join(words, `\s+`)
+	case "regex":
+		expr = pattern
+	default:
+		return matcher{}, errors.New(fmt.Sprintf("Mode '%s' not found", mode))
+	}
+
+	re, err := regexp.Compile(`(?i)` + expr)
+	if err != nil {
+		return matcher{}, errors.Wrap(err, "compile pattern")
+	}
+
+	return matcher{re: re, bounded: mode != "regex"}, nil
+}
+
+// find returns start and end of non-empty keywords in s
+func (m matcher) find(s string) [][2]int {
+	found := make([][2]int, 0)
+	if !m.bounded {
+		for _, loc := range m.re.FindAllStringIndex(s, -1) {
+			if loc[0] < loc[1] {
+				found = append(found, [2]int{loc[0], loc[1]})
+			}
+		}
+		return found
+	}
+
+	for pos := 0; pos < len(s); {
+		loc := m.re.FindStringIndex(s[pos:])
+		if loc == nil {
+			break
+		}
+		start, end := pos+loc[0], pos+loc[1]
+		if start < end && !wordBefore(s, start) && !wordAfter(s, end) {
+			found = append(found, [2]int{start, end})
+			pos = end
+			continue
+		}
+		// keyword inside a longer word, the next match may start within it
+		_, size := utf8.DecodeRuneInString(s[start:])
+		pos = start + size
+	}
+
+	return found
+}
+
+// wordBefore reports whether a letter or digit precedes position i in s
+func wordBefore(s string, i int) bool {
+	r, size := utf8.DecodeLastRuneInString(s[:i])
+
+	return size > 0 && isWordRune(r)
+}
+
+// wordAfter reports whether a letter or digit follows position i in s
+func wordAfter(s string, i int) bool {
+	r, size := utf8.DecodeRuneInString(s[i:])
+
+	return size > 0 && isWordRune(r)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r)
+}
+
+func concordance(cx models.Complex, m matcher, fields []models.Field, width int) []models.Concordance {
+	date := ""
+	if !cx.Date.IsZero() {
+		date = cx.Date.Format(models.DateLayout)
+	}
+
+	lines := make([]models.Concordance, 0)
+	for _, field := range fields {
+		if !field.IsText() {
+			continue
+		}
+		for _, value := range cx.Values(field) {
+			for _, loc := range m.find(value) {
+				start, end := loc[0], loc[1]
+				lines = append(lines, models.Concordance{
+					Url:     cx.Url,
+					Profile: cx.Profile,
+					Date:    date,
+					Field:   field,
+					Left:    lastWords(value[:start], width),
+					Keyword: value[start:end],
+					Right:   firstWords(value[end:], width),
+				})
+			}
+		}
+	}
+
+	return lines
+}
+
+func lastWords(s string, n int) string {
+	words := strings.Fields(s)
+	if len(words) > n {
+		words = words[len(words)-n:]
+	}
+	left := strings.Join(words, " ")
+	if last, _ := utf8.DecodeLastRuneInString(s); unicode.IsSpace(last) {
+		left += " "
+	}
+
+	return left
+}
+
+func firstWords(s string, n int) string {
+	words := strings.Fields(s)
+	if len(words) > n {
+		words = words[:n]
+	}
+	right := strings.Join(words, " ")
+	if first, _ := utf8.DecodeRuneInString(s); unicode.IsSpace(first) {
+		right = " " + right
+	}
+
+	return right
+}
+
+// sortLines sorts by the nearest left or right neighbour of the keyword
+func sortLines(lines []models.Concordance, by string) {
+	key := func(l models.Concordance) string {
+		switch by {
+		case "left":
+			words := strings.Fields(l.Left)
+			for i, j := 0, len(words)-1; i < j; i, j = i+1, j-1 {
+				words[i], words[j] = words[j], words[i]
+			}
+			return strings.ToLower(strings.Join(words, " "))
+		case "right":
+			return strings.ToLower(strings.TrimSpace(l.Right))
+		}
+		return ""
+	}
+	if by != "left" && by != "right" {
+		return
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return key(lines[i]) < key(lines[j])
+	})
+}
@@ -0,0 +1,76 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatcherFind(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		pattern string
+		text    string
+		want    []string
+	}{
+		{"adjacent words with comma", "word", "die", "die, die Partei", []string{"die", "die"}},
+		{"adjacent words with space", "word", "Merkel", "Merkel Merkel", []string{"Merkel", "Merkel"}},
+		{"string start and end", "word", "Merkel", "Merkel sagt, es war Merkel", []string{"Merkel", "Merkel"}},
+		{"whole string", "word", "Merkel", "Merkel", []string{"Merkel"}},
+		{"inside longer word", "word", "die", "diese Diele", nil},
+		{"longer word before match", "word", "die", "Bündnisdie die", []string{"die"}},
+		{"case insensitive", "word", "zeitenwende", "Die Zeitenwende", []string{"Zeitenwende"}},
+		{"umlaut word", "word", "für", "für, für", []string{"für", "für"}},
+		{"umlaut neighbour", "word", "Bar", "Bär Barä Bar", []string{"Bar"}},
+		{"umlaut after match", "word", "Gr", "Grün Gr", []string{"Gr"}},
+		{"digits are word characters", "word", "19", "2019 19", []string{"19"}},
+		{"phrase adjacent", "phrase", "die Partei", "die Partei die  Partei", []string{"die Partei", "die  Partei"}},
+		{"phrase inside word", "phrase", "die Partei", "Studie Parteien", nil},
+		{"regex unbounded", "regex", "ung\\b", "Regierung, Zeitung", []string{"ung", "ung"}},
+		{"regex empty matches", "regex", "x*", "abc", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := compile(tt.mode, tt.pattern)
+			if err != nil {
+				t.Fatalf("compile() error = %v", err)
+			}
+			var got []string
+			for _, loc := range m.find(tt.text) {
+				got = append(got, tt.text[loc[0]:loc[1]])
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("find(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContextWords(t *testing.T) {
+	tests := []struct {
+		name      string
+		left      string
+		right     string
+		words     int
+		wantLeft  string
+		wantRight string
+	}{
+		{"ascii spaces kept", "sagte die ", " zur Presse", 5, "sagte die ", " zur Presse"},
+		{"letters ending in 0xA0 and 0x85 bytes", "Er sagte voilà", "Å und", 5, "Er sagte voilà", "Å und"},
+		{"no-break space", "voilà\u00a0", "\u00a0à Paris", 5, "voilà ", " à Paris"},
+		{"word limit", "eins zwei drei vier", "eins zwei drei vier", 2, "drei vier", "eins zwei"},
+		{"empty", "", "", 5, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lastWords(tt.left, tt.words); got != tt.wantLeft {
+				t.Errorf("lastWords(%q) = %q, want %q", tt.left, got, tt.wantLeft)
+			}
+			if got := firstWords(tt.right, tt.words); got != tt.wantRight {
+				t.Errorf("firstWords(%q) = %q, want %q", tt.right, got, tt.wantRight)
+			}
+		})
+	}
+}
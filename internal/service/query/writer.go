@@ -0,0 +1,77 @@
+package query
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"github.com/sku4/mslu-parser/models"
+	"html/template"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+func writeText(w io.Writer, lines []models.Concordance) error {
+	width := 0
+	for _, l := range lines {
+		if n := utf8.RuneCountInString(l.Left); n > width {
+			width = n
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, l := range lines {
+		pad := strings.Repeat(" ", width-utf8.RuneCountInString(l.Left))
+		_, _ = fmt.Fprintf(bw, "%s%s[%s]%s\t%s %s %s\n", pad, l.Left, l.Keyword, l.Right, l.Profile, l.Date, l.Field)
+	}
+
+	return bw.Flush()
+}
+
+func writeCsv(w io.Writer, lines []models.Concordance) error {
+	cw := csv.NewWriter(w)
+	cw.UseCRLF = true
+	_ = cw.Write([]string{"url", "profile", "date", "field", "left", "keyword", "right"})
+	for _, l := range lines {
+		_ = cw.Write([]string{l.Url, l.Profile, l.Date, string(l.Field), l.Left, l.Keyword, l.Right})
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+var htmlTemplate = template.Must(template.New("kwic").Parse(`<!DOCTYPE html>
+<html lang="de">
+<head>
+<meta charset="utf-8">
+<title>KWIC: {{.Pattern}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+td { padding: 2px 6px; white-space: nowrap; }
+td.left { text-align: right; }
+td.kw { font-weight: bold; color: #b00; }
+td.meta { color: #777; font-size: 0.85em; }
+</style>
+</head>
+<body>
+<h1>{{.Pattern}} ({{len .Lines}})</h1>
+<table>
+{{range .Lines}}<tr>
+<td class="left">{{.Left}}</td><td class="kw">{{.Keyword}}</td><td>{{.Right}}</td>
+<td class="meta"><a href="{{.Url}}">{{.Profile}}</a> {{.Date}} {{.Field}}</td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+func writeHtml(w io.Writer, pattern string, lines []models.Concordance) error {
+	return htmlTemplate.Execute(w, struct {
+		Pattern string
+		Lines   []models.Concordance
+	}{
+		Pattern: pattern,
+		Lines:   lines,
+	})
+}
@@ -3,22 +3,85 @@ package service
 import (
 	"context"
 	"github.com/sku4/mslu-parser/internal/repository"
+	"github.com/sku4/mslu-parser/internal/service/analyzer"
+	"github.com/sku4/mslu-parser/internal/service/corpus"
+	"github.com/sku4/mslu-parser/internal/service/export"
+	"github.com/sku4/mslu-parser/internal/service/job"
 	"github.com/sku4/mslu-parser/internal/service/parser"
+	"github.com/sku4/mslu-parser/internal/service/query"
+	"github.com/sku4/mslu-parser/internal/service/stats"
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/models/cli"
+	"io"
 )
 
 //go:generate mockgen -source=service.go -destination=mocks/service.go
 
 type Parser interface {
 	Run(context.Context) error
+	RetryFailed(context.Context) error
 	Shutdown() error
+	Report() *models.Report
+	Doctor(context.Context, io.Writer) error
+}
+
+type Exporter interface {
+	Export(context.Context, io.Writer) error
+}
+
+type Querier interface {
+	Query(context.Context, io.Writer) error
+}
+
+type Stats interface {
+	Freq(context.Context, io.Writer) error
+	Colloc(context.Context, io.Writer) error
+	Headlines(context.Context, io.Writer) error
+	Readability(context.Context, io.Writer) error
+	Lexicon(context.Context, io.Writer) error
+	Entities(context.Context, io.Writer) error
+}
+
+type Analyzer interface {
+	Analyze(*models.Complex)
+	AnalyzeAll(context.Context) error
+}
+
+type Jobs interface {
+	Create(cli.Arguments) (models.Job, error)
+	List() []models.Job
+	Get(id string) (models.Job, error)
+	Cancel(id string) (models.Job, error)
+	Export(ctx context.Context, id string, w io.Writer) error
+	Process(context.Context)
+}
+
+type Corpus interface {
+	Articles(context.Context, models.ArticleQuery) (models.ArticlePage, error)
+	Article(ctx context.Context, id string) (models.Article, error)
+	Counts(ctx context.Context, by string, filter models.Filter) ([]models.Count, error)
 }
 
 type Service struct {
 	Parser
+	Exporter
+	Querier
+	Stats
+	Analyzer
+	Jobs
+	Corpus
 }
 
 func NewService(repos *repository.Repository) *Service {
+	analyzerService := analyzer.NewService(repos)
+
 	return &Service{
-		Parser: parser.NewService(repos),
+		Parser:   parser.NewService(repos, analyzerService),
+		Analyzer: analyzerService,
+		Exporter: export.NewService(repos),
+		Querier:  query.NewService(repos),
+		Stats:    stats.NewService(repos),
+		Jobs:     job.NewService(repos, analyzerService),
+		Corpus:   corpus.NewService(repos),
 	}
 }
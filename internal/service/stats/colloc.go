@@ -0,0 +1,182 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/sku4/mslu-parser/models/cli"
+	"github.com/sku4/mslu-parser/pkg/tokenizer"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+type cooccurrences struct {
+	tokens int
+	node   int
+	// span counts word positions within windows of node occurrences, windows end at sentence edges
+	span  int
+	words map[string]int
+	cooc  map[string]int
+}
+
+type collocate struct {
+	word               string
+	freq, cooc         int
+	ll, mi, t, logDice float64
+}
+
+var measures = map[string]func(c collocate) float64{
+	"ll":      func(c collocate) float64 { return c.ll },
+	"mi":      func(c collocate) float64 { return c.mi },
+	"t":       func(c collocate) float64 { return c.t },
+	"logdice": func(c collocate) float64 { return c.logDice },
+}
+
+// Colloc ranks words co-occurring with the node word within a window by association measures
+func (s *Service) Colloc(ctx context.Context, w io.Writer) error {
+	args := cli.GetStatsArgs(ctx)
+	node := strings.ToLower(strings.TrimSpace(args.Node))
+	if node == "" {
+		return errors.New("node word not set")
+	}
+	measure, ok := measures[args.Measure]
+	if !ok {
+		return errors.New(fmt.Sprintf("Measure '%s' not found", args.Measure))
+	}
+	if args.Window < 1 {
+		return errors.New("window must be positive")
+	}
+	dims, err := parseDimensions(args.By)
+	if err != nil {
+		return err
+	}
+	fields, err := textFields(args.Fields)
+	if err != nil {
+		return err
+	}
+	complexes, err := s.complexes(ctx)
+	if err != nil {
+		return err
+	}
+
+	groups := make(map[group]*cooccurrences)
+	for _, cx := range complexes {
+		for _, field := range fields {
+			g := dims.group(cx, field)
+			co, ok := groups[g]
+			if !ok {
+				co = &cooccurrences{words: make(map[string]int), cooc: make(map[string]int)}
+				groups[g] = co
+			}
+			for _, value := range cx.Values(field) {
+				co.add(value, node, args.Window)
+			}
+		}
+	}
+
+	keys := make([]group, 0, len(groups))
+	for g := range groups {
+		keys = append(keys, g)
+	}
+	sortGroups(keys)
+
+	t := table{
+		Name: "Collocations",
+		Header: []string{"outlet", "field", "month", "node", "collocate", "f_node", "f_collocate", "f_cooc",
+			"ll", "mi", "t", "logdice"},
+	}
+	for _, g := range keys {
+		co := groups[g]
+		collocates := co.collocates(args.Min)
+		sort.Slice(collocates, func(i, j int) bool {
+			a, b := measure(collocates[i]), measure(collocates[j])
+			if a != b {
+				return a > b
+			}
+			return collocates[i].word < collocates[j].word
+		})
+		if args.Top > 0 && len(collocates) > args.Top {
+			collocates = collocates[:args.Top]
+		}
+		for _, c := range collocates {
+			t.Rows = append(t.Rows, []interface{}{
+				g.Outlet, string(g.Field), g.Month, node, c.word, co.node, c.freq, c.cooc, c.ll, c.mi, c.t, c.logDice,
+			})
+		}
+	}
+
+	return write(w, args.Format, []table{t})
+}
+
+// add counts words of text and words within window around node inside each sentence
+func (co *cooccurrences) add(text, node string, window int) {
+	for _, sentence := range tokenizer.Tokenize(text) {
+		words := sentence.Words(true)
+		co.tokens += len(words)
+		for i, word := range words {
+			co.words[word]++
+			if word != node {
+				continue
+			}
+			co.node++
+			for j := i - window; j <= i+window; j++ {
+				if j >= 0 && j < len(words) && j != i {
+					co.span++
+					co.cooc[words[j]]++
+				}
+			}
+		}
+	}
+}
+
+// collocates computes association measures from the surface co-occurrence contingency table
+func (co *cooccurrences) collocates(min int) []collocate {
+	n := float64(co.tokens)
+	r1 := float64(co.span)
+	collocates := make([]collocate, 0, len(co.cooc))
+	for word, o := range co.cooc {
+		if o < min {
+			continue
+		}
+		c1 := float64(co.words[word])
+		o11 := float64(o)
+		o12 := math.Max(r1-o11, 0)
+		o21 := math.Max(c1-o11, 0)
+		o22 := math.Max(n-r1-c1+o11, 0)
+		e11 := r1 * c1 / n
+		// signed log-likelihood, negative for words avoiding the node
+		ll := logLikelihood(o11, o12, o21, o22)
+		if o11 < e11 {
+			ll = -ll
+		}
+
+		collocates = append(collocates, collocate{
+			word:    word,
+			freq:    co.words[word],
+			cooc:    o,
+			ll:      ll,
+			mi:      math.Log2(o11 / e11),
+			t:       (o11 - e11) / math.Sqrt(o11),
+			logDice: 14 + math.Log2(2*o11/(float64(co.node)+c1)),
+		})
+	}
+
+	return collocates
+}
+
+// logLikelihood is Dunning's G2 for a 2x2 contingency table
+func logLikelihood(o11, o12, o21, o22 float64) float64 {
+	n := o11 + o12 + o21 + o22
+	r1, r2 := o11+o12, o21+o22
+	c1, c2 := o11+o21, o12+o22
+	term := func(o, e float64) float64 {
+		if o == 0 || e == 0 {
+			return 0
+		}
+		return o * math.Log(o/e)
+	}
+
+	return 2 * (term(o11, r1*c1/n) + term(o12, r1*c2/n) + term(o21, r2*c1/n) + term(o22, r2*c2/n))
+}
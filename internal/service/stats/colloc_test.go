@@ -0,0 +1,82 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCollocates(t *testing.T) {
+	co := &cooccurrences{words: make(map[string]int), cooc: make(map[string]int)}
+	// node krieg twice, window 2 ends at sentence edges: kostet geld, der endet
+	co.add("Krieg kostet Geld. Der Krieg endet. Geld regiert.", "krieg", 2)
+	if co.tokens != 8 || co.node != 2 || co.span != 4 {
+		t.Fatalf("add() tokens, node, span = %d, %d, %d, want 8, 2, 4", co.tokens, co.node, co.span)
+	}
+
+	// contingency table of N = 8 tokens and R1 = 4 window positions, E11 = R1 * C1 / N
+	want := map[string]collocate{
+		// C1 = 1, O11 = 1, E11 = 0.5, O12 = 3, O21 = 0, O22 = 4
+		"kostet": {
+			freq: 1, cooc: 1,
+			ll: 2 * (math.Log(1/0.5) + 3*math.Log(3/3.5) + 4*math.Log(4/3.5)),
+			mi: 1, t: 0.5, logDice: 14 + math.Log2(2.0/3),
+		},
+		// C1 = 2, O11 = 1, E11 = 1 as for independent words
+		"geld": {freq: 2, cooc: 1, ll: 0, mi: 0, t: 0, logDice: 13},
+	}
+	want["der"], want["endet"] = want["kostet"], want["kostet"]
+
+	collocates := co.collocates(1)
+	if len(collocates) != len(want) {
+		t.Fatalf("collocates() = %d collocates, want %d", len(collocates), len(want))
+	}
+	for _, c := range collocates {
+		w, ok := want[c.word]
+		if !ok {
+			t.Errorf("collocates() unexpected %q", c.word)
+			continue
+		}
+		if c.freq != w.freq || c.cooc != w.cooc {
+			t.Errorf("collocates() %q freq, cooc = %d, %d, want %d, %d", c.word, c.freq, c.cooc, w.freq, w.cooc)
+		}
+		scores := []struct {
+			name      string
+			got, want float64
+		}{
+			{"ll", c.ll, w.ll},
+			{"mi", c.mi, w.mi},
+			{"t", c.t, w.t},
+			{"logdice", c.logDice, w.logDice},
+		}
+		for _, s := range scores {
+			if math.Abs(s.got-s.want) > 1e-9 {
+				t.Errorf("collocates() %q %s = %v, want %v", c.word, s.name, s.got, s.want)
+			}
+		}
+	}
+
+	if got := co.collocates(2); len(got) != 0 {
+		t.Errorf("collocates(min 2) = %d collocates, want 0", len(got))
+	}
+}
+
+func TestLogLikelihood(t *testing.T) {
+	tests := []struct {
+		name               string
+		o11, o12, o21, o22 float64
+		want               float64
+	}{
+		{"independent", 10, 20, 30, 60, 0},
+		// expected 5, 5, 5, 5
+		{"associated", 10, 0, 0, 10, 2 * (10*math.Log(2) + 10*math.Log(2))},
+		{"empty cells", 0, 5, 5, 0, 2 * (5*math.Log(2) + 5*math.Log(2))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := logLikelihood(tt.o11, tt.o12, tt.o21, tt.o22); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("logLikelihood() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/sku4/mslu-parser/internal/service/analyzer/entity"
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/models/cli"
+	"io"
+	"strings"
+)
+
+var entityTypes = []string{
+	models.EntityPerson, models.EntityPlace, models.EntityOrganization, models.EntityParty,
+}
+
+// Entities writes named entity mentions per group and the share of articles mentioning each entity
+func (s *Service) Entities(ctx context.Context, w io.Writer) error {
+	args := cli.GetStatsArgs(ctx)
+	dims, err := parseDimensions(args.By)
+	if err != nil {
+		return err
+	}
+	fields, err := textFields(args.Fields)
+	if err != nil {
+		return err
+	}
+	types, err := parseEntityTypes(args.Types)
+	if err != nil {
+		return err
+	}
+	complexes, err := s.complexes(ctx)
+	if err != nil {
+		return err
+	}
+
+	var analyzer *entity.Entity
+	type totals struct {
+		articles int
+		types    map[string]int
+		mentions map[string]int
+		names    map[string]int
+	}
+	groups := make(map[group]*totals)
+	for i := range complexes {
+		cx := &complexes[i]
+		if cx.Analysis.Entities == nil {
+			if analyzer == nil {
+				analyzer = entity.New()
+			}
+			analyzer.Analyze(cx)
+		}
+
+		for _, field := range fields {
+			if strings.Join(cx.Values(field), "") == "" {
+				continue
+			}
+			g := dims.group(*cx, field)
+			t, ok := groups[g]
+			if !ok {
+				t = &totals{
+					types:    make(map[string]int),
+					mentions: make(map[string]int),
+					names:    make(map[string]int),
+				}
+				groups[g] = t
+			}
+			t.articles++
+
+			seen := make(map[string]bool)
+			for _, e := range cx.Analysis.Entities {
+				if e.Field != field || !types[e.Type] {
+					continue
+				}
+				key := e.Type + "\t" + e.Name
+				t.types[e.Type]++
+				t.mentions[key]++
+				if !seen[key] {
+					seen[key] = true
+					t.names[key]++
+				}
+			}
+		}
+	}
+
+	keys := make([]group, 0, len(groups))
+	for g := range groups {
+		keys = append(keys, g)
+	}
+	sortGroups(keys)
+
+	summary := table{
+		Name:   "Summary",
+		Header: []string{"outlet", "field", "month", "articles"},
+	}
+	summary.Header = append(summary.Header, entityTypes...)
+	entities := table{
+		Name:   "Entities",
+		Header: []string{"outlet", "field", "month", "type", "name", "mentions", "articles", "article_share"},
+	}
+	for _, g := range keys {
+		t := groups[g]
+		row := []interface{}{g.Outlet, string(g.Field), g.Month, t.articles}
+		for _, typ := range entityTypes {
+			row = append(row, t.types[typ])
+		}
+		summary.Rows = append(summary.Rows, row)
+
+		for _, key := range topWords(t.mentions, args.Top) {
+			if t.mentions[key] < args.Min {
+				continue
+			}
+			typ, name, _ := strings.Cut(key, "\t")
+			entities.Rows = append(entities.Rows, []interface{}{
+				g.Outlet, string(g.Field), g.Month, typ, name, t.mentions[key], t.names[key],
+				ratio(t.names[key], t.articles),
+			})
+		}
+	}
+
+	return write(w, args.Format, []table{summary, entities})
+}
+
+// parseEntityTypes parses comma separated entity types, empty string means all types
+func parseEntityTypes(s string) (map[string]bool, error) {
+	types := make(map[string]bool)
+	if strings.TrimSpace(s) == "" {
+		for _, typ := range entityTypes {
+			types[typ] = true
+		}
+		return types, nil
+	}
+
+	for _, name := range strings.Split(s, ",") {
+		typ := strings.ToLower(strings.TrimSpace(name))
+		valid := false
+		for _, t := range entityTypes {
+			valid = valid || t == typ
+		}
+		if !valid {
+			return nil, errors.New(fmt.Sprintf("unknown entity type '%s'", name))
+		}
+		types[typ] = true
+	}
+
+	return types, nil
+}
@@ -0,0 +1,157 @@
+package stats
+
+import (
+	"context"
+	"github.com/sku4/mslu-parser/models/cli"
+	"github.com/sku4/mslu-parser/pkg/tokenizer"
+	"io"
+	"sort"
+	"strings"
+)
+
+const maxN = 3
+
+type frequencies struct {
+	tokens int
+	ngrams [maxN + 1]map[string]int
+}
+
+func newFrequencies() *frequencies {
+	f := &frequencies{}
+	for n := 1; n <= maxN; n++ {
+		f.ngrams[n] = make(map[string]int)
+	}
+
+	return f
+}
+
+// Freq writes word form frequencies, n-gram counts and hapax counts split by groups
+func (s *Service) Freq(ctx context.Context, w io.Writer) error {
+	args := cli.GetStatsArgs(ctx)
+	dims, err := parseDimensions(args.By)
+	if err != nil {
+		return err
+	}
+	fields, err := textFields(args.Fields)
+	if err != nil {
+		return err
+	}
+	complexes, err := s.complexes(ctx)
+	if err != nil {
+		return err
+	}
+
+	groups := make(map[group]*frequencies)
+	for _, cx := range complexes {
+		for _, field := range fields {
+			g := dims.group(cx, field)
+			freq, ok := groups[g]
+			if !ok {
+				freq = newFrequencies()
+				groups[g] = freq
+			}
+			for _, value := range cx.Values(field) {
+				freq.add(value, args.Lower)
+			}
+		}
+	}
+
+	keys := sortedGroups(groups)
+	tables := []table{summaryTable(keys, groups)}
+	for n := 1; n <= maxN; n++ {
+		if args.N > 0 && args.N != n {
+			continue
+		}
+		tables = append(tables, ngramTable(n, keys, groups, args.Min, args.Top))
+	}
+
+	return write(w, args.Format, tables)
+}
+
+// add counts word n-grams of text, n-grams do not cross sentence boundaries
+func (f *frequencies) add(text string, lower bool) {
+	for _, sentence := range tokenizer.Tokenize(text) {
+		words := sentence.Words(lower)
+		f.tokens += len(words)
+		for n := 1; n <= maxN; n++ {
+			for i := 0; i+n <= len(words); i++ {
+				f.ngrams[n][strings.Join(words[i:i+n], " ")]++
+			}
+		}
+	}
+}
+
+func sortedGroups(groups map[group]*frequencies) []group {
+	keys := make([]group, 0, len(groups))
+	for g := range groups {
+		keys = append(keys, g)
+	}
+	sortGroups(keys)
+
+	return keys
+}
+
+func summaryTable(keys []group, groups map[group]*frequencies) table {
+	t := table{
+		Name:   "Summary",
+		Header: []string{"outlet", "field", "month", "tokens", "types", "hapax", "ttr", "bigrams", "trigrams"},
+	}
+	for _, g := range keys {
+		freq := groups[g]
+		hapax := 0
+		for _, c := range freq.ngrams[1] {
+			if c == 1 {
+				hapax++
+			}
+		}
+		ttr := 0.0
+		if freq.tokens > 0 {
+			ttr = float64(len(freq.ngrams[1])) / float64(freq.tokens)
+		}
+		t.Rows = append(t.Rows, []interface{}{
+			g.Outlet, string(g.Field), g.Month, freq.tokens, len(freq.ngrams[1]), hapax, ttr,
+			len(freq.ngrams[2]), len(freq.ngrams[3]),
+		})
+	}
+
+	return t
+}
+
+func ngramTable(n int, keys []group, groups map[group]*frequencies, min, top int) table {
+	names := [...]string{"", "Unigrams", "Bigrams", "Trigrams"}
+	t := table{
+		Name:   names[n],
+		Header: []string{"outlet", "field", "month", "ngram", "count", "per_million"},
+	}
+
+	type item struct {
+		ngram string
+		count int
+	}
+	for _, g := range keys {
+		freq := groups[g]
+		items := make([]item, 0, len(freq.ngrams[n]))
+		for ngram, count := range freq.ngrams[n] {
+			if count >= min {
+				items = append(items, item{ngram, count})
+			}
+		}
+		sort.Slice(items, func(i, j int) bool {
+			if items[i].count != items[j].count {
+				return items[i].count > items[j].count
+			}
+			return items[i].ngram < items[j].ngram
+		})
+		if top > 0 && len(items) > top {
+			items = items[:top]
+		}
+		for _, it := range items {
+			t.Rows = append(t.Rows, []interface{}{
+				g.Outlet, string(g.Field), g.Month, it.ngram, it.count,
+				float64(it.count) * 1e6 / float64(freq.tokens),
+			})
+		}
+	}
+
+	return t
+}
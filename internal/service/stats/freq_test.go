@@ -0,0 +1,142 @@
+package stats
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"github.com/sku4/mslu-parser/internal/repository"
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/models/cli"
+	"reflect"
+	"testing"
+)
+
+type fakeExcel struct {
+	repository.Excel
+	complexes []models.Complex
+}
+
+func (e fakeExcel) GetComplexes(context.Context) ([]models.Complex, error) {
+	return e.complexes, nil
+}
+
+func TestFrequenciesAdd(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		lower  bool
+		tokens int
+		ngrams [maxN + 1]map[string]int
+	}{
+		{
+			name:   "n-grams within sentences",
+			text:   "Der Hund bellt. Der Hund schläft!",
+			lower:  true,
+			tokens: 6,
+			ngrams: [maxN + 1]map[string]int{
+				1: {"der": 2, "hund": 2, "bellt": 1, "schläft": 1},
+				2: {"der hund": 2, "hund bellt": 1, "hund schläft": 1},
+				3: {"der hund bellt": 1, "der hund schläft": 1},
+			},
+		},
+		{
+			name:   "word forms keep case",
+			text:   "Hund, und hund 3,5 Prozent.",
+			tokens: 5,
+			ngrams: [maxN + 1]map[string]int{
+				1: {"Hund": 1, "und": 1, "hund": 1, "3,5": 1, "Prozent": 1},
+				2: {"Hund und": 1, "und hund": 1, "hund 3,5": 1, "3,5 Prozent": 1},
+				3: {"Hund und hund": 1, "und hund 3,5": 1, "hund 3,5 Prozent": 1},
+			},
+		},
+		{
+			name:   "punctuation only",
+			text:   "…",
+			ngrams: [maxN + 1]map[string]int{1: {}, 2: {}, 3: {}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFrequencies()
+			f.add(tt.text, tt.lower)
+			if f.tokens != tt.tokens {
+				t.Errorf("add() tokens = %d, want %d", f.tokens, tt.tokens)
+			}
+			for n := 1; n <= maxN; n++ {
+				if !reflect.DeepEqual(f.ngrams[n], tt.ngrams[n]) {
+					t.Errorf("add() %d-grams = %v, want %v", n, f.ngrams[n], tt.ngrams[n])
+				}
+			}
+		})
+	}
+}
+
+func TestFrequencyTables(t *testing.T) {
+	g := group{Outlet: "zeit", Field: models.FieldLead, Month: allGroup}
+	f := newFrequencies()
+	f.add("Der Hund bellt. Der Hund schläft.", true)
+	groups := map[group]*frequencies{g: f}
+
+	// 6 tokens of 4 types, bellt and schläft occur once
+	summary := summaryTable([]group{g}, groups)
+	want := [][]interface{}{{"zeit", "lead", allGroup, 6, 4, 2, 4.0 / 6, 3, 2}}
+	if !reflect.DeepEqual(summary.Rows, want) {
+		t.Errorf("summaryTable() rows = %v, want %v", summary.Rows, want)
+	}
+
+	bigrams := ngramTable(2, []group{g}, groups, 2, 0)
+	want = [][]interface{}{{"zeit", "lead", allGroup, "der hund", 2, 2e6 / 6}}
+	if !reflect.DeepEqual(bigrams.Rows, want) {
+		t.Errorf("ngramTable(min 2) rows = %v, want %v", bigrams.Rows, want)
+	}
+
+	// equal counts are ordered by n-gram
+	unigrams := ngramTable(1, []group{g}, groups, 1, 3)
+	want = [][]interface{}{
+		{"zeit", "lead", allGroup, "der", 2, 2e6 / 6},
+		{"zeit", "lead", allGroup, "hund", 2, 2e6 / 6},
+		{"zeit", "lead", allGroup, "bellt", 1, 1e6 / 6},
+	}
+	if !reflect.DeepEqual(unigrams.Rows, want) {
+		t.Errorf("ngramTable(top 3) rows = %v, want %v", unigrams.Rows, want)
+	}
+}
+
+func TestFreq(t *testing.T) {
+	s := NewService(&repository.Repository{Excel: fakeExcel{complexes: []models.Complex{
+		{Profile: "zeit", Title: "Scholz verteidigt die Zeitenwende", Lead: "Nicht gezählt."},
+		{Profile: "spiegel", Title: "Merkel trifft Macron"},
+		{Profile: "zeit", Title: "Die Zeitenwende"},
+	}}})
+	ctx := cli.SetStatsArgs(context.Background(), cli.StatsArguments{
+		Format: "csv",
+		Fields: "title",
+		By:     "outlet",
+		N:      1,
+		Min:    2,
+		Lower:  true,
+	})
+
+	var b bytes.Buffer
+	if err := s.Freq(ctx, &b); err != nil {
+		t.Fatalf("Freq() error = %v", err)
+	}
+	r := csv.NewReader(&b)
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	want := [][]string{
+		{"outlet", "field", "month", "tokens", "types", "hapax", "ttr", "bigrams", "trigrams"},
+		{"spiegel", "*", "*", "3", "3", "3", "1.0000", "2", "1"},
+		{"zeit", "*", "*", "6", "4", "2", "0.6667", "3", "2"},
+		{"outlet", "field", "month", "ngram", "count", "per_million"},
+		{"zeit", "*", "*", "die", "2", "333333.3333"},
+		{"zeit", "*", "*", "zeitenwende", "2", "333333.3333"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("Freq() = %q, want %q", records, want)
+	}
+}
@@ -0,0 +1,114 @@
+package stats
+
+import (
+	"context"
+	"github.com/sku4/mslu-parser/internal/service/analyzer/headline"
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/models/cli"
+	"io"
+	"sort"
+)
+
+var headlineFeatures = []struct {
+	name  string
+	value func(h *models.Headline) bool
+}{
+	{"question", func(h *models.Headline) bool { return h.Question }},
+	{"exclamation", func(h *models.Headline) bool { return h.Exclamation }},
+	{"quote", func(h *models.Headline) bool { return h.Quote }},
+	{"colon", func(h *models.Headline) bool { return h.Colon }},
+	{"verbless", func(h *models.Headline) bool { return h.Verbless }},
+	{"imperative", func(h *models.Headline) bool { return h.Imperative }},
+	{"number", func(h *models.Headline) bool { return h.Number }},
+	{"ellipsis", func(h *models.Headline) bool { return h.Ellipsis }},
+}
+
+// Headlines writes headline features per article and their shares per outlet
+func (s *Service) Headlines(ctx context.Context, w io.Writer) error {
+	args := cli.GetStatsArgs(ctx)
+	dims, err := parseDimensions(args.By)
+	if err != nil {
+		return err
+	}
+	complexes, err := s.complexes(ctx)
+	if err != nil {
+		return err
+	}
+
+	articles := table{
+		Name:   "Headlines",
+		Header: []string{"url", "outlet", "date", "overtitle", "title"},
+	}
+	summary := table{
+		Name:   "Summary",
+		Header: []string{"outlet", "articles"},
+	}
+	for _, f := range headlineFeatures {
+		articles.Header = append(articles.Header, f.name)
+		summary.Header = append(summary.Header, f.name)
+	}
+	articles.Header = append(articles.Header, "words", "chars", "overtitle_words", "overtitle_chars")
+	summary.Header = append(summary.Header, "avg_words", "avg_chars", "avg_overtitle_words", "avg_overtitle_chars")
+
+	type totals struct {
+		articles                                int
+		features                                []int
+		words, chars, overTitleWords, overChars int
+	}
+	outlets := make(map[string]*totals)
+	for _, cx := range complexes {
+		h := cx.Analysis.Headline
+		if h == nil {
+			classified := headline.Classify(cx.Title, cx.OverTitle)
+			h = &classified
+		}
+
+		outlet := cx.Profile
+		if !dims.outlet {
+			outlet = allGroup
+		}
+		t, ok := outlets[outlet]
+		if !ok {
+			t = &totals{features: make([]int, len(headlineFeatures))}
+			outlets[outlet] = t
+		}
+		t.articles++
+		t.words += h.Words
+		t.chars += h.Chars
+		t.overTitleWords += h.OverTitleWords
+		t.overChars += h.OverTitleChars
+
+		date := ""
+		if !cx.Date.IsZero() {
+			date = cx.Date.Format(models.DateLayout)
+		}
+		row := []interface{}{cx.Url, cx.Profile, date, cx.OverTitle, cx.Title}
+		for i, f := range headlineFeatures {
+			value := f.value(h)
+			if value {
+				t.features[i]++
+			}
+			row = append(row, value)
+		}
+		row = append(row, h.Words, h.Chars, h.OverTitleWords, h.OverTitleChars)
+		articles.Rows = append(articles.Rows, row)
+	}
+
+	names := make([]string, 0, len(outlets))
+	for outlet := range outlets {
+		names = append(names, outlet)
+	}
+	sort.Strings(names)
+	for _, outlet := range names {
+		t := outlets[outlet]
+		n := float64(t.articles)
+		row := []interface{}{outlet, t.articles}
+		for _, c := range t.features {
+			row = append(row, float64(c)/n)
+		}
+		row = append(row, float64(t.words)/n, float64(t.chars)/n, float64(t.overTitleWords)/n, float64(t.overChars)/n)
+		summary.Rows = append(summary.Rows, row)
+	}
+
+	return write(w, args.Format, []table{summary, articles})
+}
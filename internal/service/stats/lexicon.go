@@ -0,0 +1,153 @@
+package stats
+
+import (
+	"context"
+	"github.com/sku4/mslu-parser/internal/service/analyzer/lexicon"
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/models/cli"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Lexicon writes compound and anglicism counts per article and outlet
+func (s *Service) Lexicon(ctx context.Context, w io.Writer) error {
+	args := cli.GetStatsArgs(ctx)
+	dims, err := parseDimensions(args.By)
+	if err != nil {
+		return err
+	}
+	dims.field = false
+	complexes, err := s.complexes(ctx)
+	if err != nil {
+		return err
+	}
+
+	var analyzer *lexicon.Lexicon
+	type totals struct {
+		articles, words, nouns, compounds, anglicisms int
+		compoundWords                                 map[string]int
+		compoundParts                                 map[string]string
+		anglicismWords                                map[string]int
+		anglicismReasons                              map[string]string
+	}
+	groups := make(map[group]*totals)
+	articles := table{
+		Name:   "Articles",
+		Header: []string{"url", "outlet", "date", "words", "nouns", "compounds", "anglicisms", "anglicism_list"},
+	}
+	for i := range complexes {
+		cx := &complexes[i]
+		if cx.Analysis.Lexicon == nil {
+			if analyzer == nil {
+				analyzer = lexicon.New()
+			}
+			analyzer.Analyze(cx)
+		}
+		lex := cx.Analysis.Lexicon
+
+		g := dims.group(*cx, allGroup)
+		t, ok := groups[g]
+		if !ok {
+			t = &totals{
+				compoundWords:    make(map[string]int),
+				compoundParts:    make(map[string]string),
+				anglicismWords:   make(map[string]int),
+				anglicismReasons: make(map[string]string),
+			}
+			groups[g] = t
+		}
+		t.articles++
+		t.words += lex.Words
+		t.nouns += lex.Nouns
+		t.compounds += len(lex.Compounds)
+		t.anglicisms += len(lex.Anglicisms)
+		found := make([]string, 0, len(lex.Anglicisms))
+		for _, c := range lex.Compounds {
+			word := strings.ToLower(c.Word)
+			t.compoundWords[word]++
+			t.compoundParts[word] = strings.Join(c.Parts, "+")
+		}
+		for _, a := range lex.Anglicisms {
+			word := strings.ToLower(a.Word)
+			t.anglicismWords[word]++
+			t.anglicismReasons[word] = a.Reason
+			found = append(found, a.Word)
+		}
+
+		date := ""
+		if !cx.Date.IsZero() {
+			date = cx.Date.Format(models.DateLayout)
+		}
+		articles.Rows = append(articles.Rows, []interface{}{
+			cx.Url, cx.Profile, date, lex.Words, lex.Nouns, len(lex.Compounds), len(lex.Anglicisms),
+			strings.Join(found, ", "),
+		})
+	}
+
+	keys := make([]group, 0, len(groups))
+	for g := range groups {
+		keys = append(keys, g)
+	}
+	sortGroups(keys)
+
+	summary := table{
+		Name: "Summary",
+		Header: []string{"outlet", "month", "articles", "words", "nouns", "compounds", "compound_ratio",
+			"anglicisms", "anglicism_ratio"},
+	}
+	compounds := table{
+		Name:   "Compounds",
+		Header: []string{"outlet", "month", "compound", "parts", "count"},
+	}
+	anglicisms := table{
+		Name:   "Anglicisms",
+		Header: []string{"outlet", "month", "anglicism", "reason", "count"},
+	}
+	for _, g := range keys {
+		t := groups[g]
+		summary.Rows = append(summary.Rows, []interface{}{
+			g.Outlet, g.Month, t.articles, t.words, t.nouns, t.compounds, ratio(t.compounds, t.nouns),
+			t.anglicisms, ratio(t.anglicisms, t.words),
+		})
+		for _, word := range topWords(t.compoundWords, args.Top) {
+			compounds.Rows = append(compounds.Rows, []interface{}{
+				g.Outlet, g.Month, word, t.compoundParts[word], t.compoundWords[word],
+			})
+		}
+		for _, word := range topWords(t.anglicismWords, args.Top) {
+			anglicisms.Rows = append(anglicisms.Rows, []interface{}{
+				g.Outlet, g.Month, word, t.anglicismReasons[word], t.anglicismWords[word],
+			})
+		}
+	}
+
+	return write(w, args.Format, []table{summary, anglicisms, compounds, articles})
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+
+	return float64(a) / float64(b)
+}
+
+// topWords returns words sorted by count, all words if top is 0
+func topWords(counts map[string]int, top int) []string {
+	words := make([]string, 0, len(counts))
+	for word := range counts {
+		words = append(words, word)
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if counts[words[i]] != counts[words[j]] {
+			return counts[words[i]] > counts[words[j]]
+		}
+		return words[i] < words[j]
+	})
+	if top > 0 && len(words) > top {
+		words = words[:top]
+	}
+
+	return words
+}
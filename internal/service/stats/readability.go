@@ -0,0 +1,111 @@
+package stats
+
+import (
+	"context"
+	"github.com/sku4/mslu-parser/internal/service/analyzer/readability"
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/models/cli"
+	"io"
+)
+
+var readabilityMetrics = []struct {
+	name  string
+	value func(r *models.Readability) float64
+}{
+	{"flesch_amstad", func(r *models.Readability) float64 { return r.FleschAmstad }},
+	{"wiener_sachtext", func(r *models.Readability) float64 { return r.WienerSachtext }},
+	{"avg_sentence_length", func(r *models.Readability) float64 { return r.AvgSentenceLength }},
+	{"syllables_per_word", func(r *models.Readability) float64 { return r.SyllablesPerWord }},
+	{"long_word_ratio", func(r *models.Readability) float64 { return r.LongWordRatio }},
+}
+
+// bodyField labels body text scores, subtitles and image titles measured together
+const bodyField models.Field = "body"
+
+// Readability writes lead and body readability per article and averages per outlet, field and period
+func (s *Service) Readability(ctx context.Context, w io.Writer) error {
+	args := cli.GetStatsArgs(ctx)
+	dims, err := parseDimensions(args.By)
+	if err != nil {
+		return err
+	}
+	// lead and body scores are never averaged together
+	dims.field = true
+	complexes, err := s.complexes(ctx)
+	if err != nil {
+		return err
+	}
+
+	articles := table{
+		Name:   "Readability",
+		Header: []string{"url", "outlet", "field", "date", "sentences", "words", "syllables"},
+	}
+	summary := table{
+		Name:   "Summary",
+		Header: []string{"outlet", "field", "month", "articles"},
+	}
+	for _, m := range readabilityMetrics {
+		articles.Header = append(articles.Header, m.name)
+		summary.Header = append(summary.Header, m.name)
+	}
+
+	type totals struct {
+		articles int
+		sums     []float64
+	}
+	groups := make(map[group]*totals)
+	for _, cx := range complexes {
+		lead, body := cx.Analysis.Readability, cx.Analysis.BodyReadability
+		if lead == nil {
+			lead = readability.Measure(cx.Lead)
+		}
+		if body == nil {
+			body = readability.Measure(readability.Body(cx)...)
+		}
+
+		date := ""
+		if !cx.Date.IsZero() {
+			date = cx.Date.Format(models.DateLayout)
+		}
+		for _, part := range []struct {
+			field models.Field
+			r     *models.Readability
+		}{{models.FieldLead, lead}, {bodyField, body}} {
+			r := part.r
+			if r == nil {
+				continue
+			}
+			g := dims.group(cx, part.field)
+			t, ok := groups[g]
+			if !ok {
+				t = &totals{sums: make([]float64, len(readabilityMetrics))}
+				groups[g] = t
+			}
+			t.articles++
+
+			row := []interface{}{cx.Url, cx.Profile, string(part.field), date, r.Sentences, r.Words, r.Syllables}
+			for i, m := range readabilityMetrics {
+				value := m.value(r)
+				t.sums[i] += value
+				row = append(row, value)
+			}
+			articles.Rows = append(articles.Rows, row)
+		}
+	}
+
+	keys := make([]group, 0, len(groups))
+	for g := range groups {
+		keys = append(keys, g)
+	}
+	sortGroups(keys)
+	for _, g := range keys {
+		t := groups[g]
+		row := []interface{}{g.Outlet, string(g.Field), g.Month, t.articles}
+		for _, sum := range t.sums {
+			row = append(row, sum/float64(t.articles))
+		}
+		summary.Rows = append(summary.Rows, row)
+	}
+
+	return write(w, args.Format, []table{summary, articles})
+}
@@ -0,0 +1,131 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/sku4/mslu-parser/internal/repository"
+	"github.com/sku4/mslu-parser/models"
+	"github.com/sku4/mslu-parser/models/cli"
+	"io"
+	"sort"
+	"strings"
+)
+
+const allGroup = "*"
+
+type Service struct {
+	repos *repository.Repository
+}
+
+func NewService(repos *repository.Repository) *Service {
+	return &Service{
+		repos: repos,
+	}
+}
+
+// group splits statistics by outlet, field and month, disabled dimensions hold allGroup
+type group struct {
+	Outlet string
+	Field  models.Field
+	Month  string
+}
+
+type dimensions struct {
+	outlet, field, month bool
+}
+
+func parseDimensions(by string) (dimensions, error) {
+	d := dimensions{}
+	for _, name := range strings.Split(by, ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "outlet":
+			d.outlet = true
+		case "field":
+			d.field = true
+		case "month":
+			d.month = true
+		default:
+			return d, errors.New(fmt.Sprintf("unknown split '%s'", name))
+		}
+	}
+
+	return d, nil
+}
+
+func (d dimensions) group(cx models.Complex, field models.Field) group {
+	g := group{Outlet: allGroup, Field: allGroup, Month: allGroup}
+	if d.outlet {
+		g.Outlet = cx.Profile
+	}
+	if d.field {
+		g.Field = field
+	}
+	if d.month {
+		g.Month = "unknown"
+		if !cx.Date.IsZero() {
+			g.Month = cx.Date.Format("2006-01")
+		}
+	}
+
+	return g
+}
+
+func sortGroups(keys []group) {
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.Outlet != b.Outlet {
+			return a.Outlet < b.Outlet
+		}
+		if a.Field != b.Field {
+			return a.Field < b.Field
+		}
+		return a.Month < b.Month
+	})
+}
+
+// complexes loads repository articles passing stats filter
+func (s *Service) complexes(ctx context.Context) ([]models.Complex, error) {
+	args := cli.GetStatsArgs(ctx)
+	filter, err := models.NewFilter(args.Profile, args.From, args.To)
+	if err != nil {
+		return nil, err
+	}
+	filter.Unique = args.Unique
+
+	complexes, err := s.repos.Excel.GetComplexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return filter.Apply(complexes), nil
+}
+
+// textFields parses fields argument keeping text fields only
+func textFields(s string) ([]models.Field, error) {
+	fields, err := models.ParseFields(s)
+	if err != nil {
+		return nil, err
+	}
+
+	text := make([]models.Field, 0, len(fields))
+	for _, field := range fields {
+		if field.IsText() {
+			text = append(text, field)
+		}
+	}
+
+	return text, nil
+}
+
+func write(w io.Writer, format string, tables []table) error {
+	switch format {
+	case "csv":
+		return writeCsv(w, tables)
+	case "xlsx":
+		return writeXlsx(w, tables)
+	}
+
+	return errors.New(fmt.Sprintf("Format '%s' not found", format))
+}
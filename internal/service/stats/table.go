@@ -0,0 +1,89 @@
+package stats
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/xuri/excelize/v2"
+	"io"
+	"strconv"
+)
+
+type table struct {
+	Name   string
+	Header []string
+	Rows   [][]interface{}
+}
+
+// writeCsv writes tables one after another separated by an empty line
+func writeCsv(w io.Writer, tables []table) error {
+	cw := csv.NewWriter(w)
+	cw.UseCRLF = true
+	for i, t := range tables {
+		if i > 0 {
+			_ = cw.Write(nil)
+		}
+		_ = cw.Write(t.Header)
+		record := make([]string, len(t.Header))
+		for _, row := range t.Rows {
+			for j, v := range row {
+				record[j] = format(v)
+			}
+			_ = cw.Write(record)
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// writeXlsx writes every table to its own sheet
+func writeXlsx(w io.Writer, tables []table) error {
+	f := excelize.NewFile()
+	defer func() {
+		_ = f.Close()
+	}()
+
+	headerStyle, _ := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	for i, t := range tables {
+		if i == 0 {
+			_ = f.SetSheetName("Sheet1", t.Name)
+		} else if _, err := f.NewSheet(t.Name); err != nil {
+			return err
+		}
+
+		sw, err := f.NewStreamWriter(t.Name)
+		if err != nil {
+			return err
+		}
+		header := make([]interface{}, len(t.Header))
+		for j, h := range t.Header {
+			header[j] = excelize.Cell{StyleID: headerStyle, Value: h}
+		}
+		if err = sw.SetRow("A1", header); err != nil {
+			return err
+		}
+		for j, row := range t.Rows {
+			if err = sw.SetRow("A"+strconv.Itoa(j+2), row); err != nil {
+				return err
+			}
+		}
+		if err = sw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	_, err := f.WriteTo(w)
+
+	return err
+}
+
+func format(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', 4, 64)
+	}
+
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,96 @@
+package models
+
+// Analysis holds results of text analyzers stored alongside the article, entities are stored
+// even when empty so that null marks articles not analyzed for entities yet
+type Analysis struct {
+	Headline        *Headline    `json:"headline,omitempty"`
+	Readability     *Readability `json:"readability,omitempty"`
+	BodyReadability *Readability `json:"body_readability,omitempty"`
+	Lexicon         *Lexicon     `json:"lexicon,omitempty"`
+	Language        *Language    `json:"language,omitempty"`
+	Fingerprint     *Fingerprint `json:"fingerprint,omitempty"`
+	Entities        []Entity     `json:"entities"`
+}
+
+// Headline holds structural features of title and overtitle
+type Headline struct {
+	Question       bool `json:"question"`
+	Exclamation    bool `json:"exclamation"`
+	Quote          bool `json:"quote"`
+	Colon          bool `json:"colon"`
+	Verbless       bool `json:"verbless"`
+	Imperative     bool `json:"imperative"`
+	Number         bool `json:"number"`
+	Ellipsis       bool `json:"ellipsis"`
+	Words          int  `json:"words"`
+	Chars          int  `json:"chars"`
+	OverTitleWords int  `json:"overtitle_words"`
+	OverTitleChars int  `json:"overtitle_chars"`
+}
+
+// Readability holds readability scores of the lead or of the body text made of subtitles and image titles
+type Readability struct {
+	Sentences         int     `json:"sentences"`
+	Words             int     `json:"words"`
+	Syllables         int     `json:"syllables"`
+	AvgSentenceLength float64 `json:"avg_sentence_length"`
+	SyllablesPerWord  float64 `json:"syllables_per_word"`
+	LongWordRatio     float64 `json:"long_word_ratio"`
+	FleschAmstad      float64 `json:"flesch_amstad"`
+	WienerSachtext    float64 `json:"wiener_sachtext"`
+}
+
+// Lexicon holds compounds and anglicisms found in article text
+type Lexicon struct {
+	Words      int         `json:"words"`
+	Nouns      int         `json:"nouns"`
+	Compounds  []Compound  `json:"compounds,omitempty"`
+	Anglicisms []Anglicism `json:"anglicisms,omitempty"`
+}
+
+type Compound struct {
+	Word  string   `json:"word"`
+	Parts []string `json:"parts"`
+}
+
+type Anglicism struct {
+	Word   string `json:"word"`
+	Reason string `json:"reason"`
+}
+
+// Language holds ISO 639-1 codes of field languages, empty when undetermined
+type Language struct {
+	Article     string   `json:"article"`
+	Title       string   `json:"title"`
+	OverTitle   string   `json:"overtitle"`
+	Lead        string   `json:"lead"`
+	Subtitles   []string `json:"subtitles,omitempty"`
+	ImageTitles []string `json:"imagetitles,omitempty"`
+}
+
+// Fingerprint holds SimHash of article text and its near-duplicate cluster,
+// duplicate is set when an earlier article of the cluster exists
+type Fingerprint struct {
+	SimHash   string `json:"simhash"`
+	Cluster   string `json:"cluster"`
+	Duplicate bool   `json:"duplicate"`
+}
+
+const (
+	EntityPerson       = "person"
+	EntityPlace        = "place"
+	EntityOrganization = "organization"
+	EntityParty        = "party"
+)
+
+// Entity is a named entity span in a text field, offsets count runes of the value,
+// item is the value index of list fields and name is the canonical gazetteer name
+type Entity struct {
+	Field Field  `json:"field"`
+	Item  int    `json:"item,omitempty"`
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Text  string `json:"text"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
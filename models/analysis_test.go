@@ -0,0 +1,34 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAnalysisEntitiesRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		entities []Entity
+		analyzed bool
+	}{
+		{"not analyzed", nil, false},
+		{"analyzed without entities", []Entity{}, true},
+		{"analyzed with entities", []Entity{{Type: EntityPerson, Name: "Olaf Scholz"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(Analysis{Entities: tt.entities})
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			a := Analysis{}
+			if err = json.Unmarshal(b, &a); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if got := a.Entities != nil; got != tt.analyzed {
+				t.Errorf("entities of %s analyzed = %v, want %v", b, got, tt.analyzed)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+)
+
+// Version is the text of an article replaced by a later download
+type Version struct {
+	Replaced    time.Time `json:"replaced"`
+	Date        time.Time `json:"date"`
+	Author      string    `json:"author"`
+	Title       string    `json:"title"`
+	OverTitle   string    `json:"overtitle"`
+	Lead        string    `json:"lead"`
+	Subtitles   []string  `json:"subtitles"`
+	ImageTitles []string  `json:"imagetitles"`
+}
+
+// NewVersion returns text of complex replaced at time
+func NewVersion(cx Complex, replaced time.Time) Version {
+	return Version{
+		Replaced:    replaced,
+		Date:        cx.Date,
+		Author:      cx.Author,
+		Title:       cx.Title,
+		OverTitle:   cx.OverTitle,
+		Lead:        cx.Lead,
+		Subtitles:   cx.Subtitles,
+		ImageTitles: cx.ImageTitles,
+	}
+}
+
+// Changed reports whether complex differs from the version in text or author
+func (v Version) Changed(cx Complex) bool {
+	return v.Title != cx.Title || v.OverTitle != cx.OverTitle || v.Lead != cx.Lead || v.Author != cx.Author ||
+		strings.Join(v.Subtitles, "\n") != strings.Join(cx.Subtitles, "\n") ||
+		strings.Join(v.ImageTitles, "\n") != strings.Join(cx.ImageTitles, "\n")
+}
+
+// Article is a complex as served by the corpus api, versions are ordered from oldest
+type Article struct {
+	ID          string     `json:"id"`
+	Url         string     `json:"url"`
+	Profile     string     `json:"profile"`
+	Date        *time.Time `json:"date"`
+	Author      string     `json:"author"`
+	Title       string     `json:"title"`
+	OverTitle   string     `json:"overtitle"`
+	Lead        string     `json:"lead"`
+	Subtitles   []string   `json:"subtitles"`
+	ImageTitles []string   `json:"imagetitles"`
+	Analysis    *Analysis  `json:"analysis,omitempty"`
+	Versions    []Version  `json:"versions,omitempty"`
+}
+
+func NewArticle(cx Complex) Article {
+	article := Article{
+		ID:          ArticleID(cx.Url),
+		Url:         cx.Url,
+		Profile:     cx.Profile,
+		Author:      cx.Author,
+		Title:       cx.Title,
+		OverTitle:   cx.OverTitle,
+		Lead:        cx.Lead,
+		Subtitles:   cx.Subtitles,
+		ImageTitles: cx.ImageTitles,
+	}
+	if !cx.Date.IsZero() {
+		date := cx.Date
+		article.Date = &date
+	}
+	if article.Subtitles == nil {
+		article.Subtitles = []string{}
+	}
+	if article.ImageTitles == nil {
+		article.ImageTitles = []string{}
+	}
+
+	return article
+}
+
+// ArticleID returns stable identifier of article url
+func ArticleID(url string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(url))
+
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// FindComplex returns complex with url of article id
+func FindComplex(complexes []Complex, id string) (Complex, error) {
+	for _, cx := range complexes {
+		if ArticleID(cx.Url) == id {
+			return cx, nil
+		}
+	}
+
+	return Complex{}, ArticleNotFoundError
+}
+
+// ArticleQuery selects a page of articles, Contains is searched in Fields
+type ArticleQuery struct {
+	Filter
+	Contains string
+	Fields   []Field
+	// HasCaptions keeps articles with image captions when true, without when false
+	HasCaptions *bool
+	Page        int
+	PerPage     int
+}
+
+type ArticlePage struct {
+	Total    int       `json:"total"`
+	Page     int       `json:"page"`
+	PerPage  int       `json:"per_page"`
+	Articles []Article `json:"articles"`
+}
+
+// Count is the article count of an outlet and month, empty when not grouped by it
+type Count struct {
+	Outlet   string `json:"outlet,omitempty"`
+	Month    string `json:"month,omitempty"`
+	Articles int    `json:"articles"`
+}
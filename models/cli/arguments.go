@@ -1,19 +1,30 @@
 package cli
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
+// Arguments of crawl, json names match flag names, backoff is set by flags only
 type Arguments struct {
-	Profile            string
-	Login              string
-	Password           string
-	ZeitMode           string
-	ZeitType           string
-	SpiegelSuchbegriff string
-	SpiegelZeitraum    int
-	SpiegelInhalt      string
-	SpiegelSegments    string
-	Count              int
-	Update             bool
+	Profile            string        `json:"profile"`
+	Login              string        `json:"login"`
+	Password           string        `json:"pass,omitempty"`
+	ZeitMode           string        `json:"zeit_mode"`
+	ZeitType           string        `json:"zeit_type"`
+	SpiegelSuchbegriff string        `json:"spiegel_suchbegriff"`
+	SpiegelZeitraum    int           `json:"spiegel_zeitraum"`
+	SpiegelInhalt      string        `json:"spiegel_inhalt"`
+	SpiegelSegments    string        `json:"spiegel_segments"`
+	Count              int           `json:"count"`
+	Update             bool          `json:"update"`
+	Language           string        `json:"language"`
+	Normalize          string        `json:"normalize"`
+	MinFillRate        float64       `json:"min_fill_rate"`
+	FillFields         string        `json:"fill_fields"`
+	FillSample         int           `json:"fill_sample"`
+	Retries            int           `json:"retries"`
+	RetryBackoff       time.Duration `json:"-"`
 }
 
 type argsKey struct{}
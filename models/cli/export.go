@@ -0,0 +1,31 @@
+package cli
+
+import "context"
+
+type ExportArguments struct {
+	Format      string
+	Output      string
+	Profile     string
+	From        string
+	To          string
+	Fields      string
+	Joiner      string
+	Split       bool
+	Match       string
+	MatchFields string
+	Unique      bool
+	// Urls limits export to articles of a crawl job, empty means all articles
+	Urls []string
+}
+
+type exportArgsKey struct{}
+
+func SetExportArgs(ctx context.Context, args ExportArguments) context.Context {
+	return context.WithValue(ctx, exportArgsKey{}, args)
+}
+
+func GetExportArgs(ctx context.Context) ExportArguments {
+	contextArgs, _ := ctx.Value(exportArgsKey{}).(ExportArguments)
+
+	return contextArgs
+}
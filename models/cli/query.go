@@ -0,0 +1,28 @@
+package cli
+
+import "context"
+
+type QueryArguments struct {
+	Pattern string
+	Mode    string
+	Fields  string
+	Context int
+	Sort    string
+	Format  string
+	Output  string
+	Profile string
+	From    string
+	To      string
+}
+
+type queryArgsKey struct{}
+
+func SetQueryArgs(ctx context.Context, args QueryArguments) context.Context {
+	return context.WithValue(ctx, queryArgsKey{}, args)
+}
+
+func GetQueryArgs(ctx context.Context) QueryArguments {
+	contextArgs, _ := ctx.Value(queryArgsKey{}).(QueryArguments)
+
+	return contextArgs
+}
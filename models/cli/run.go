@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+type runIDKey struct{}
+
+// NewRunID returns run identifier of start time and random suffix, e.g. 20230115-093000-1a2b3c
+func NewRunID() string {
+	b := make([]byte, 3)
+	_, _ = rand.Read(b)
+
+	return time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(b)
+}
+
+func SetRunID(ctx context.Context, runID string) context.Context {
+	return context.WithValue(ctx, runIDKey{}, runID)
+}
+
+func GetRunID(ctx context.Context) string {
+	runID, _ := ctx.Value(runIDKey{}).(string)
+
+	return runID
+}
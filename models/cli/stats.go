@@ -0,0 +1,34 @@
+package cli
+
+import "context"
+
+type StatsArguments struct {
+	Format  string
+	Output  string
+	Fields  string
+	By      string
+	N       int
+	Min     int
+	Top     int
+	Lower   bool
+	Node    string
+	Window  int
+	Measure string
+	Types   string
+	Profile string
+	From    string
+	To      string
+	Unique  bool
+}
+
+type statsArgsKey struct{}
+
+func SetStatsArgs(ctx context.Context, args StatsArguments) context.Context {
+	return context.WithValue(ctx, statsArgsKey{}, args)
+}
+
+func GetStatsArgs(ctx context.Context) StatsArguments {
+	contextArgs, _ := ctx.Value(statsArgsKey{}).(StatsArguments)
+
+	return contextArgs
+}
@@ -1,11 +1,28 @@
 package models
 
+import "time"
+
 type Complex struct {
 	Title           string
 	OverTitle       string
 	Lead            string
 	Subtitles       []string
 	ImageTitles     []string
+	Profile         string
+	Author          string
+	Date            time.Time
+	Analysis        Analysis
+	Raw             *Raw
 	TooManyRequests bool
 	ExcelUrl
 }
+
+// Raw holds extracted values before normalization, nil when normalization changed nothing
+type Raw struct {
+	Title       string   `json:"title"`
+	OverTitle   string   `json:"overtitle"`
+	Lead        string   `json:"lead"`
+	Subtitles   []string `json:"subtitles,omitempty"`
+	ImageTitles []string `json:"imagetitles,omitempty"`
+	Author      string   `json:"author"`
+}
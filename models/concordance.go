@@ -0,0 +1,12 @@
+package models
+
+// Concordance is a keyword-in-context line
+type Concordance struct {
+	Url     string
+	Profile string
+	Date    string
+	Field   Field
+	Left    string
+	Keyword string
+	Right   string
+}
@@ -6,4 +6,6 @@ var (
 	ArticlesNotFoundError = errors.New("articles not found")
 	ArticleNotFoundError  = errors.New("article not found")
 	ProfileNotInitError   = errors.New("profile not init")
+	JobNotFoundError      = errors.New("job not found")
+	PageOutOfRangeError   = errors.New("page out of range")
 )
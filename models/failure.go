@@ -0,0 +1,96 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Snapshot is the response of an article which failed extraction, kept to debug selectors
+type Snapshot struct {
+	Url     string      `json:"url"`
+	Profile string      `json:"profile"`
+	Status  int         `json:"status"`
+	Header  http.Header `json:"header"`
+	Error   string      `json:"error"`
+	Time    time.Time   `json:"time"`
+	Html    []byte      `json:"-"`
+}
+
+// SnapshotError wraps download error with the response snapshot
+type SnapshotError struct {
+	Err      error
+	Snapshot *Snapshot
+}
+
+func (e *SnapshotError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *SnapshotError) Unwrap() error {
+	return e.Err
+}
+
+// DownloadError classifies article download error by Reason constant of its class
+type DownloadError struct {
+	Class  string
+	Status int
+	Err    error
+}
+
+func (e *DownloadError) Error() string {
+	if e.Status != 0 {
+		return fmt.Sprintf("%s (status %d): %s", e.Class, e.Status, e.Err.Error())
+	}
+
+	return fmt.Sprintf("%s: %s", e.Class, e.Err.Error())
+}
+
+func (e *DownloadError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether the error is temporary, network and server errors are
+func (e *DownloadError) Retryable() bool {
+	return e.Class == ReasonNetwork || e.Class == ReasonServer
+}
+
+// StatusError returns classified error of response status, nil for success and redirect statuses
+func StatusError(status int) error {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return &DownloadError{Class: ReasonServer, Status: status, Err: errors.New(http.StatusText(status))}
+	case status >= http.StatusBadRequest:
+		return &DownloadError{Class: ReasonClient, Status: status, Err: errors.New(http.StatusText(status))}
+	}
+
+	return nil
+}
+
+// ErrorClass returns class of download error, ReasonDownload for unclassified errors
+func ErrorClass(err error) string {
+	var downloadErr *DownloadError
+	if errors.As(err, &downloadErr) {
+		return downloadErr.Class
+	}
+
+	return ReasonDownload
+}
+
+// DeadLetter is an url failed download, retryable failures are queued again after NextRetry
+type DeadLetter struct {
+	Url         string    `json:"url"`
+	Profile     string    `json:"profile"`
+	Reason      string    `json:"reason"`
+	Error       string    `json:"error"`
+	Attempts    int       `json:"attempts"`
+	FirstFailed time.Time `json:"first_failed"`
+	LastFailed  time.Time `json:"last_failed"`
+	NextRetry   time.Time `json:"next_retry"`
+}
+
+// Due reports whether the url may be downloaded again at t, urls failed permanently are never due
+func (d *DeadLetter) Due(t time.Time) bool {
+	return !d.NextRetry.IsZero() && !t.Before(d.NextRetry)
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Field string
+
+const (
+	FieldUrl         Field = "url"
+	FieldProfile     Field = "profile"
+	FieldDate        Field = "date"
+	FieldAuthor      Field = "author"
+	FieldTitle       Field = "title"
+	FieldOverTitle   Field = "overtitle"
+	FieldLead        Field = "lead"
+	FieldSubtitles   Field = "subtitles"
+	FieldImageTitles Field = "imagetitles"
+	FieldAnalysis    Field = "analysis"
+	FieldRaw         Field = "raw"
+)
+
+// Fields lists every exportable field in output order
+var Fields = []Field{
+	FieldUrl, FieldProfile, FieldDate, FieldAuthor, FieldTitle, FieldOverTitle, FieldLead, FieldSubtitles, FieldImageTitles,
+	FieldAnalysis, FieldRaw,
+}
+
+// TextFields lists the fields holding article text
+var TextFields = []Field{
+	FieldTitle, FieldOverTitle, FieldLead, FieldSubtitles, FieldImageTitles,
+}
+
+// ParseFields parses a comma separated list of field names, empty string means all fields
+func ParseFields(s string) ([]Field, error) {
+	if strings.TrimSpace(s) == "" {
+		return Fields, nil
+	}
+
+	fields := make([]Field, 0)
+	for _, name := range strings.Split(s, ",") {
+		f := Field(strings.ToLower(strings.TrimSpace(name)))
+		if !f.valid() {
+			return nil, fmt.Errorf("unknown field '%s'", name)
+		}
+		fields = append(fields, f)
+	}
+
+	return fields, nil
+}
+
+func (f Field) valid() bool {
+	for _, field := range Fields {
+		if field == f {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsList reports whether the field holds several values
+func (f Field) IsList() bool {
+	return f == FieldSubtitles || f == FieldImageTitles
+}
+
+// IsText reports whether the field holds article text
+func (f Field) IsText() bool {
+	for _, field := range TextFields {
+		if field == f {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Values returns field values of complex, scalar fields are returned as one element slice
+func (c *Complex) Values(f Field) []string {
+	switch f {
+	case FieldUrl:
+		return []string{c.Url}
+	case FieldProfile:
+		return []string{c.Profile}
+	case FieldDate:
+		if c.Date.IsZero() {
+			return []string{""}
+		}
+		return []string{c.Date.Format(time.RFC3339)}
+	case FieldAuthor:
+		return []string{c.Author}
+	case FieldTitle:
+		return []string{c.Title}
+	case FieldOverTitle:
+		return []string{c.OverTitle}
+	case FieldLead:
+		return []string{c.Lead}
+	case FieldSubtitles:
+		return c.Subtitles
+	case FieldImageTitles:
+		return c.ImageTitles
+	case FieldAnalysis:
+		b, _ := json.Marshal(c.Analysis)
+		return []string{string(b)}
+	case FieldRaw:
+		if c.Raw == nil {
+			return []string{""}
+		}
+		b, _ := json.Marshal(c.Raw)
+		return []string{string(b)}
+	}
+
+	return nil
+}
+
+// ContainsPhrase reports whether a text field of fields contains lower case phrase, all repositories
+// search with these semantics
+func (c *Complex) ContainsPhrase(phrase string, fields []Field) bool {
+	for _, field := range fields {
+		if !field.IsText() {
+			continue
+		}
+		for _, value := range c.Values(field) {
+			if strings.Contains(strings.ToLower(value), phrase) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
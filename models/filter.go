@@ -0,0 +1,74 @@
+package models
+
+import (
+	"github.com/pkg/errors"
+	"time"
+)
+
+const DateLayout = "2006-01-02"
+
+type Filter struct {
+	Profile string
+	From    time.Time
+	To      time.Time
+	Unique  bool
+	// Urls limits complexes to the set, nil means all urls
+	Urls map[string]struct{}
+}
+
+// NewFilter parses filter dates in DateLayout, to date is inclusive
+func NewFilter(profile, from, to string) (filter Filter, err error) {
+	filter.Profile = profile
+	if from != "" {
+		if filter.From, err = time.Parse(DateLayout, from); err != nil {
+			return filter, errors.Wrap(err, "parse from date")
+		}
+	}
+	if to != "" {
+		if filter.To, err = time.Parse(DateLayout, to); err != nil {
+			return filter, errors.Wrap(err, "parse to date")
+		}
+		filter.To = filter.To.AddDate(0, 0, 1)
+	}
+
+	return filter, nil
+}
+
+// Match reports whether complex passes the filter, zero values are not checked, articles
+// without date fail either date bound, unique filter drops near-duplicates of earlier articles
+func (f Filter) Match(c Complex) bool {
+	if f.Profile != "" && f.Profile != c.Profile {
+		return false
+	}
+	if (!f.From.IsZero() || !f.To.IsZero()) && c.Date.IsZero() {
+		return false
+	}
+	if !f.From.IsZero() && c.Date.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !c.Date.Before(f.To) {
+		return false
+	}
+	if f.Urls != nil {
+		if _, ok := f.Urls[c.Url]; !ok {
+			return false
+		}
+	}
+	if f.Unique && c.Analysis.Fingerprint != nil && c.Analysis.Fingerprint.Duplicate {
+		return false
+	}
+
+	return true
+}
+
+// Apply returns complexes passing the filter
+func (f Filter) Apply(complexes []Complex) []Complex {
+	filtered := make([]Complex, 0, len(complexes))
+	for _, c := range complexes {
+		if f.Match(c) {
+			filtered = append(filtered, c)
+		}
+	}
+
+	return filtered
+}
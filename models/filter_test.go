@@ -0,0 +1,50 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFilterMatch(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse(time.RFC3339, s)
+		return d
+	}
+	tests := []struct {
+		name     string
+		from, to string
+		date     time.Time
+		want     bool
+	}{
+		{"no bounds", "", "", date("2023-03-14T10:00:00Z"), true},
+		{"no bounds without date", "", "", time.Time{}, true},
+		{"from day start", "2023-03-14", "", date("2023-03-14T00:00:00Z"), true},
+		{"before from", "2023-03-14", "", date("2023-03-13T23:59:59Z"), false},
+		{"to day end", "", "2023-03-14", date("2023-03-14T23:59:59Z"), true},
+		{"after to", "", "2023-03-14", date("2023-03-15T00:00:00Z"), false},
+		{"within bounds", "2023-03-01", "2023-03-31", date("2023-03-14T10:00:00Z"), true},
+		{"one day", "2023-03-14", "2023-03-14", date("2023-03-14T10:00:00Z"), true},
+		{"from without date", "2023-03-14", "", time.Time{}, false},
+		{"to without date", "", "2023-03-14", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFilter("", tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("NewFilter() error = %v", err)
+			}
+			if got := f.Match(Complex{Date: tt.date}); got != tt.want {
+				t.Errorf("Match(%v) = %v, want %v", tt.date, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFilterInvalid(t *testing.T) {
+	for _, bounds := range [][2]string{{"14.03.2023", ""}, {"", "2023-3-14"}} {
+		if _, err := NewFilter("", bounds[0], bounds[1]); err == nil {
+			t.Errorf("NewFilter(%q, %q) error = nil, want error", bounds[0], bounds[1])
+		}
+	}
+}
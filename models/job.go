@@ -0,0 +1,26 @@
+package models
+
+import (
+	"github.com/sku4/mslu-parser/models/cli"
+	"time"
+)
+
+const (
+	JobPending  = "pending"
+	JobRunning  = "running"
+	JobDone     = "done"
+	JobFailed   = "failed"
+	JobCanceled = "canceled"
+)
+
+// Job is a crawl run started over http, report shows progress while the job is running
+type Job struct {
+	ID        string        `json:"id"`
+	Status    string        `json:"status"`
+	Arguments cli.Arguments `json:"arguments"`
+	Created   time.Time     `json:"created"`
+	Started   *time.Time    `json:"started,omitempty"`
+	Finished  *time.Time    `json:"finished,omitempty"`
+	Error     string        `json:"error,omitempty"`
+	Report    *Report       `json:"report,omitempty"`
+}
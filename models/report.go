@@ -0,0 +1,143 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	ReasonExists          = "exists"
+	ReasonLanguage        = "language"
+	ReasonDeadLetter      = "dead_letter"
+	ReasonTooManyRequests = "too_many_requests"
+	ReasonDownload        = "download"
+	ReasonSave            = "save"
+	// download error classes
+	ReasonNetwork = "network"
+	ReasonClient  = "4xx"
+	ReasonServer  = "5xx"
+	ReasonParse   = "parse"
+	ReasonPaywall = "paywall"
+)
+
+// Report summarizes a crawl run, counts by reason are keyed by Reason constants
+type Report struct {
+	RunID           string            `json:"run_id"`
+	Arguments       map[string]string `json:"arguments"`
+	Start           time.Time         `json:"start"`
+	End             time.Time         `json:"end"`
+	Pages           int               `json:"pages_searched"`
+	Queued          int               `json:"queued"`
+	Downloaded      int               `json:"downloaded"`
+	Retried         int               `json:"retried"`
+	Filled          map[string]int    `json:"filled"`
+	New             int               `json:"new"`
+	Updated         int               `json:"updated"`
+	Skipped         map[string]int    `json:"skipped"`
+	Failed          map[string]int    `json:"failed"`
+	TooManyRequests int               `json:"too_many_requests"`
+	FailedUrls      []FailedUrl       `json:"failed_urls"`
+	Error           string            `json:"error,omitempty"`
+	// SavedUrls are kept in memory only to export articles of the run
+	SavedUrls []string `json:"-"`
+}
+
+type FailedUrl struct {
+	Url    string `json:"url"`
+	Reason string `json:"reason"`
+	Error  string `json:"error"`
+}
+
+func NewReport(runID string) *Report {
+	return &Report{
+		RunID:      runID,
+		Arguments:  make(map[string]string),
+		Start:      time.Now(),
+		Skipped:    make(map[string]int),
+		Failed:     make(map[string]int),
+		Filled:     make(map[string]int),
+		FailedUrls: make([]FailedUrl, 0),
+	}
+}
+
+// Summary returns human readable report
+func (r *Report) Summary() string {
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "Run %s\n", r.RunID)
+	_, _ = fmt.Fprintf(&b, "Started:  %s\n", r.Start.Format(time.RFC3339))
+	_, _ = fmt.Fprintf(&b, "Finished: %s (%s)\n", r.End.Format(time.RFC3339), r.End.Sub(r.Start).Round(time.Second))
+	_, _ = fmt.Fprintf(&b, "Arguments: %s\n", formatArguments(r.Arguments))
+	_, _ = fmt.Fprintf(&b, "Pages searched: %d, urls queued: %d, download retries: %d\n", r.Pages, r.Queued, r.Retried)
+	_, _ = fmt.Fprintf(&b, "Articles new: %d, updated: %d, skipped: %d, failed: %d\n",
+		r.New, r.Updated, total(r.Skipped), total(r.Failed))
+	if len(r.Skipped) > 0 {
+		_, _ = fmt.Fprintf(&b, "Skipped by reason: %s\n", formatCounts(r.Skipped))
+	}
+	if len(r.Failed) > 0 {
+		_, _ = fmt.Fprintf(&b, "Failed by reason: %s\n", formatCounts(r.Failed))
+	}
+	if r.Downloaded > 0 {
+		_, _ = fmt.Fprintf(&b, "Fill rates of %d downloaded: %s\n", r.Downloaded, r.formatFillRates())
+	}
+	_, _ = fmt.Fprintf(&b, "Too many requests: %d\n", r.TooManyRequests)
+	if r.Error != "" {
+		_, _ = fmt.Fprintf(&b, "Error: %s\n", r.Error)
+	}
+	for _, f := range r.FailedUrls {
+		_, _ = fmt.Fprintf(&b, "  %s [%s] %s\n", f.Url, f.Reason, f.Error)
+	}
+
+	return b.String()
+}
+
+func total(counts map[string]int) int {
+	sum := 0
+	for _, count := range counts {
+		sum += count
+	}
+
+	return sum
+}
+
+// FillRate returns share of downloaded articles with non-empty field
+func (r *Report) FillRate(field Field) float64 {
+	if r.Downloaded == 0 {
+		return 0
+	}
+
+	return float64(r.Filled[string(field)]) / float64(r.Downloaded)
+}
+
+func (r *Report) formatFillRates() string {
+	pairs := make([]string, 0, len(r.Filled))
+	for _, field := range Fields {
+		if _, ok := r.Filled[string(field)]; ok {
+			pairs = append(pairs, fmt.Sprintf("%s=%.2f", field, r.FillRate(field)))
+		}
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+// formatCounts formats counts as key=count pairs sorted by key
+func formatCounts(counts map[string]int) string {
+	pairs := make([]string, 0, len(counts))
+	for key, count := range counts {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", key, count))
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, " ")
+}
+
+func formatArguments(arguments map[string]string) string {
+	pairs := make([]string, 0, len(arguments))
+	for name, value := range arguments {
+		pairs = append(pairs, fmt.Sprintf("-%s=%q", name, value))
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, " ")
+}
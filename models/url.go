@@ -6,5 +6,6 @@ type ExcelUrl struct {
 }
 
 type ExcelRow struct {
-	Row int
+	Sheet string
+	Row   int
 }
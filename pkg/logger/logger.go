@@ -1,17 +1,88 @@
 package logger
 
 import (
+	"context"
+	"fmt"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+	"os"
 )
 
 var (
 	logger *zap.Logger
 )
 
+// Config configures the global logger, zero values keep production defaults: info level, json to stderr
+type Config struct {
+	Level      string
+	Format     string
+	File       string
+	MaxSize    int
+	MaxBackups int
+	MaxAge     int
+}
+
+type loggerKey struct{}
+
 func init() {
-	logger, _ = zap.NewProduction()
+	if err := Init(Config{}); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "error init logger: %s\n", err.Error())
+		logger = zap.NewNop()
+	}
+}
+
+// Init replaces the global logger, log file is rotated by size when set
+func Init(cfg Config) error {
+	level := zap.InfoLevel
+	if cfg.Level != "" {
+		if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+			return fmt.Errorf("unknown log level '%s'", cfg.Level)
+		}
+	}
+
+	var encoder zapcore.Encoder
+	switch cfg.Format {
+	case "", "json":
+		encoder = zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	case "console":
+		encoderConfig := zap.NewDevelopmentEncoderConfig()
+		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	default:
+		return fmt.Errorf("unknown log format '%s'", cfg.Format)
+	}
+
+	output := zapcore.Lock(os.Stderr)
+	if cfg.File != "" {
+		output = zapcore.AddSync(&lumberjack.Logger{
+			Filename:   cfg.File,
+			MaxSize:    cfg.MaxSize,
+			MaxBackups: cfg.MaxBackups,
+			MaxAge:     cfg.MaxAge,
+		})
+	}
+
+	logger = zap.New(zapcore.NewCore(encoder, output, level), zap.AddCaller(),
+		zap.AddStacktrace(zap.ErrorLevel))
+
+	return nil
 }
 
 func Get() *zap.SugaredLogger {
 	return logger.Sugar()
 }
+
+// WithContext returns context with logger of context extended by key-value fields
+func WithContext(ctx context.Context, keysAndValues ...interface{}) context.Context {
+	return context.WithValue(ctx, loggerKey{}, FromContext(ctx).With(keysAndValues...))
+}
+
+// FromContext returns logger stored in context, global logger when missing
+func FromContext(ctx context.Context) *zap.SugaredLogger {
+	if l, ok := ctx.Value(loggerKey{}).(*zap.SugaredLogger); ok {
+		return l
+	}
+
+	return Get()
+}
@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"time"
+)
+
+const namespace = "mslu"
+
+var (
+	SearchedPages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "searched_pages_total",
+		Help:      "Search result pages returning articles",
+	}, []string{"profile"})
+	QueuedUrls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "queued_urls_total",
+		Help:      "Article urls queued for download",
+	}, []string{"profile"})
+	DownloadedArticles = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "downloaded_articles_total",
+		Help:      "Articles downloaded and parsed",
+	}, []string{"profile"})
+	FailedArticles = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "failed_articles_total",
+		Help:      "Articles failed to download or parse by reason",
+	}, []string{"profile", "reason"})
+	FilledFields = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "filled_fields_total",
+		Help:      "Downloaded articles with non-empty field, divide by downloaded articles for fill rate",
+	}, []string{"profile", "field"})
+	SavedArticles = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "saved_articles_total",
+		Help:      "Articles saved to repository",
+	}, []string{"profile"})
+	TooManyRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "too_many_requests_total",
+		Help:      "Responses with status 429 Too Many Requests",
+	}, []string{"host"})
+	RequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "request_duration_seconds",
+		Help:      "Latency of http requests until response headers",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 30},
+	}, []string{"host"})
+	QueueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "queue_depth",
+		Help:      "Items waiting in parser channels",
+	}, []string{"queue"})
+)
+
+// Transport records latency and 429 responses per host of requests made through it
+var Transport http.RoundTripper = transport{next: http.DefaultTransport}
+
+type transport struct {
+	next http.RoundTripper
+}
+
+func (t transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	RequestDuration.WithLabelValues(req.URL.Host).Observe(time.Since(start).Seconds())
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		TooManyRequests.WithLabelValues(req.URL.Host).Inc()
+	}
+
+	return resp, err
+}
+
+// Handler returns http handler of registered metrics
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Serve exposes metrics on addr at /metrics until context is done
+func Serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package normalizer
+
+import (
+	"fmt"
+	"golang.org/x/text/unicode/norm"
+	"strings"
+)
+
+type Step string
+
+const (
+	// NFC composes characters to Unicode normalization form C
+	NFC Step = "nfc"
+	// Space maps unicode spaces to ascii space, collapses runs and trims
+	Space Step = "space"
+	// Quotes maps typographic quotes to ascii quotes
+	Quotes Step = "quotes"
+	// Dashes maps dash variants to en dash and hyphen variants to hyphen-minus
+	Dashes Step = "dashes"
+	// Hyphens removes soft hyphens and zero-width characters
+	Hyphens Step = "hyphens"
+)
+
+// Steps lists every step in application order
+var Steps = []Step{Hyphens, NFC, Quotes, Dashes, Space}
+
+var replacer = map[Step]*strings.Replacer{
+	Quotes: strings.NewReplacer(
+		"\u201e", `"`, "\u201c", `"`, "\u201d", `"`, "\u201f", `"`, "\u00ab", `"`, "\u00bb", `"`, "\u2033", `"`,
+		"\u201a", "'", "\u2018", "'", "\u2019", "'", "\u201b", "'", "\u2039", "'", "\u203a", "'", "\u2032", "'",
+	),
+	Dashes: strings.NewReplacer(
+		// hyphen, non-breaking hyphen
+		"\u2010", "-", "\u2011", "-",
+		// figure dash, em dash, horizontal bar, minus sign
+		"\u2012", "\u2013", "\u2014", "\u2013", "\u2015", "\u2013", "\u2212", "\u2013",
+	),
+	Hyphens: strings.NewReplacer(
+		// soft hyphen, zero width space, non-joiner, joiner, word joiner, byte order mark
+		"\u00ad", "", "\u200b", "", "\u200c", "", "\u200d", "", "\u2060", "", "\ufeff", "",
+	),
+}
+
+type Normalizer struct {
+	steps []Step
+}
+
+func New(steps ...Step) *Normalizer {
+	return &Normalizer{
+		steps: steps,
+	}
+}
+
+// ParseSteps parses a comma separated list of steps keeping application order,
+// empty string means all steps and "none" disables normalization
+func ParseSteps(s string) ([]Step, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return Steps, nil
+	}
+	if s == "none" {
+		return nil, nil
+	}
+
+	enabled := make(map[Step]bool)
+	for _, name := range strings.Split(s, ",") {
+		step := Step(strings.TrimSpace(name))
+		if !step.valid() {
+			return nil, fmt.Errorf("unknown normalization step '%s'", name)
+		}
+		enabled[step] = true
+	}
+	steps := make([]Step, 0, len(enabled))
+	for _, step := range Steps {
+		if enabled[step] {
+			steps = append(steps, step)
+		}
+	}
+
+	return steps, nil
+}
+
+func (s Step) valid() bool {
+	for _, step := range Steps {
+		if step == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns normalized text
+func (n *Normalizer) String(text string) string {
+	for _, step := range n.steps {
+		switch step {
+		case NFC:
+			text = norm.NFC.String(text)
+		case Space:
+			// unicode.IsSpace covers no-break and narrow no-break spaces
+			text = strings.Join(strings.Fields(text), " ")
+		default:
+			text = replacer[step].Replace(text)
+		}
+	}
+
+	return text
+}
+
+// Strings returns normalized copy of texts, nil stays nil
+func (n *Normalizer) Strings(texts []string) []string {
+	if texts == nil {
+		return nil
+	}
+	result := make([]string, len(texts))
+	for i, text := range texts {
+		result[i] = n.String(text)
+	}
+
+	return result
+}
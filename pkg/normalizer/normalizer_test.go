@@ -0,0 +1,81 @@
+package normalizer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []Step
+		text  string
+		want  string
+	}{
+		{"nfc composes", []Step{NFC}, "Cafe\u0301 Mu\u0308ller", "Caf\u00e9 M\u00fcller"},
+		{"nfc keeps composed", []Step{NFC}, "Caf\u00e9 M\u00fcller", "Caf\u00e9 M\u00fcller"},
+		{"space collapses and trims", []Step{Space}, "  Die \t Regierung\n\n tagt ", "Die Regierung tagt"},
+		{"space maps unicode spaces", []Step{Space}, "12\u202f000\u00a0Euro\u2009netto", "12 000 Euro netto"},
+		{"german quotes", []Step{Quotes}, "\u201eNein\u201c, sagte er", `"Nein", sagte er`},
+		{"guillemets", []Step{Quotes}, "\u00bbJa\u00ab und \u203aso\u2039", `"Ja" und 'so'`},
+		{"single quotes and apostrophe", []Step{Quotes}, "\u201aDas\u2018 geht\u2019s", "'Das' geht's"},
+		{"dashes to en dash", []Step{Dashes}, "Berlin\u2014Hamburg \u2212 5 \u2012 6 \u2015 7",
+			"Berlin\u2013Hamburg \u2013 5 \u2013 6 \u2013 7"},
+		{"hyphens to hyphen-minus", []Step{Dashes}, "E\u2010Mail und CDU\u2011Chef", "E-Mail und CDU-Chef"},
+		{"en dash kept", []Step{Dashes}, "2019\u20132021", "2019\u20132021"},
+		{"soft hyphens removed", []Step{Hyphens}, "Zeiten\u00adwende", "Zeitenwende"},
+		{"zero width characters removed", []Step{Hyphens}, "\ufeffBundes\u200btag\u200c\u200d\u2060", "Bundestag"},
+		{"soft hyphen before combining mark", Steps, "Cafe\u00ad\u0301", "Caf\u00e9"},
+		{"all steps", Steps, " \u201eZeiten\u00adwende\u201c \u2014 Scholz\u2019 Rede ", "\"Zeitenwende\" \u2013 Scholz' Rede"},
+		{"no steps", nil, " \u201eZeiten\u00adwende\u201c ", " \u201eZeiten\u00adwende\u201c "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.steps...).String(tt.text); got != tt.want {
+				t.Errorf("String(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrings(t *testing.T) {
+	n := New(Steps...)
+	if got := n.Strings(nil); got != nil {
+		t.Errorf("Strings(nil) = %q, want nil", got)
+	}
+	texts := []string{"Zeiten\u00adwende", " Bundestag "}
+	want := []string{"Zeitenwende", "Bundestag"}
+	if got := n.Strings(texts); !reflect.DeepEqual(got, want) {
+		t.Errorf("Strings() = %q, want %q", got, want)
+	}
+	if texts[0] != "Zeiten\u00adwende" {
+		t.Errorf("Strings() changed input to %q", texts[0])
+	}
+}
+
+func TestParseSteps(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    []Step
+		wantErr bool
+	}{
+		{"", Steps, false},
+		{" none ", nil, false},
+		{"space,nfc", []Step{NFC, Space}, false},
+		{"Quotes, hyphens, quotes", []Step{Hyphens, Quotes}, false},
+		{"nfc,lower", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseSteps(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSteps(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSteps(%q) = %v, want %v", tt.s, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package tokenizer
+
+import (
+	"strings"
+	"unicode"
+)
+
+type Kind int
+
+const (
+	Word Kind = iota
+	Number
+	Punct
+)
+
+type Token struct {
+	Form       string
+	Kind       Kind
+	SpaceAfter bool
+	// Start is the rune offset of Form in the tokenized text
+	Start int
+}
+
+type Sentence []Token
+
+// abbreviations lists german abbreviations without trailing period, matched case-insensitive
+var abbreviations = map[string]struct{}{}
+
+// wordAbbreviations are also common words or names like "Art" and "Max", they count as abbreviations
+// only before a lower case word or number like "Art. 5" and "max. zwei"
+var wordAbbreviations = map[string]struct{}{}
+
+func init() {
+	for _, a := range strings.Fields(`
+		abs abt allg bd bspw bzgl bzw ca chr co dipl dr ebd ehem engl etc evtl ff fr frz geb gegr ggf
+		hg hl hr hrsg inkl ing jh jr feb mär apr jun jul aug sep sept okt nov dez kl mio
+		mrd mrs nr o.ä od prof rd sog std str tel u.a u.ä u.u usw v.a v.chr vgl z.b z.t
+		zt zb d.h u.v.m u.s.w zit zzgl`) {
+		abbreviations[a] = struct{}{}
+	}
+	for _, a := range strings.Fields(`art jan kap max min s st`) {
+		wordAbbreviations[a] = struct{}{}
+	}
+	for _, m := range strings.Fields(`
+		januar jänner februar märz april mai juni juli august september oktober november dezember
+		jan feb mär apr jun jul aug sep sept okt nov dez`) {
+		months[m] = struct{}{}
+	}
+	for _, d := range strings.Fields(`
+		der die das den dem des am im zum zur vom beim ins ans seit ab bis jeder jede jedes jedem jeden
+		ihr ihre ihrem ihren sein seine seinem seinen`) {
+		ordinalDeterminers[d] = struct{}{}
+	}
+}
+
+// months are names of months after ordinal day numbers like "12. März"
+var months = map[string]struct{}{}
+
+// ordinalDeterminers precede ordinals before nouns like "im 20. Jahrhundert"
+var ordinalDeterminers = map[string]struct{}{}
+
+// Tokenize splits text into sentences of tokens
+func Tokenize(text string) []Sentence {
+	return Split(Tokens(text))
+}
+
+// Tokens splits text into tokens keeping abbreviations, ordinals, numbers and hyphenated compounds together
+func Tokens(text string) []Token {
+	runes := []rune(text)
+	tokens := make([]Token, 0, len(runes)/5)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		if unicode.IsSpace(r) {
+			if len(tokens) > 0 {
+				tokens[len(tokens)-1].SpaceAfter = true
+			}
+			i++
+			continue
+		}
+
+		var token Token
+		start := i
+		switch {
+		case isWordRune(r):
+			token, i = word(runes, i)
+		case r == '.' && i+2 < len(runes) && runes[i+1] == '.' && runes[i+2] == '.':
+			token = Token{Form: "...", Kind: Punct}
+			i += 3
+		default:
+			token = Token{Form: string(r), Kind: Punct}
+			i++
+		}
+		token.Start = start
+		tokens = append(tokens, token)
+	}
+
+	return tokens
+}
+
+func word(runes []rune, start int) (Token, int) {
+	i := start
+	kind := Number
+	for i < len(runes) {
+		r := runes[i]
+		if isWordRune(r) {
+			if !unicode.IsDigit(r) {
+				kind = Word
+			}
+			i++
+			continue
+		}
+		// inner joiners: compounds, decimals, apostrophes and dotted abbreviations
+		if i+1 < len(runes) && isWordRune(runes[i+1]) {
+			if r == '-' || r == '‑' || r == '\'' || r == '’' {
+				i++
+				continue
+			}
+			if (r == ',' || r == '.') && unicode.IsDigit(runes[i-1]) && unicode.IsDigit(runes[i+1]) {
+				i++
+				continue
+			}
+			if r == '.' && isDottedAbbreviation(runes[start:i+2]) {
+				i++
+				continue
+			}
+		}
+		break
+	}
+
+	form := string(runes[start:i])
+	if i < len(runes) {
+		switch runes[i] {
+		case '.':
+			prev, next := prevWord(runes, start), nextWord(runes, i+1)
+			if isAbbreviation(form, next) || isInitial(form, prev, next) || isOrdinal(form, prev, next) {
+				form += "."
+				i++
+			}
+		case '-':
+			// truncated compound like "Vor- und Nachteile"
+			if i+1 == len(runes) || unicode.IsSpace(runes[i+1]) || runes[i+1] == ',' {
+				form += "-"
+				i++
+			}
+		}
+	}
+
+	return Token{Form: form, Kind: kind}, i
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+}
+
+// isDottedAbbreviation reports whether letters are separated by single dots like "z.B" or "u.a"
+func isDottedAbbreviation(runes []rune) bool {
+	for i, r := range runes {
+		if i%2 == 0 && !unicode.IsLetter(r) {
+			return false
+		}
+		if i%2 == 1 && r != '.' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isAbbreviation(form, next string) bool {
+	if strings.Contains(form, ".") {
+		return true
+	}
+	lower := strings.ToLower(form)
+	if _, ok := abbreviations[lower]; ok {
+		return true
+	}
+	if _, ok := wordAbbreviations[lower]; !ok || next == "" {
+		return false
+	}
+	r := []rune(next)[0]
+
+	return unicode.IsLower(r) || unicode.IsDigit(r)
+}
+
+// isInitial reports whether single capital letter is an initial between names like "John F. Kennedy"
+func isInitial(form, prev, next string) bool {
+	runes := []rune(form)
+
+	return len(runes) == 1 && unicode.IsUpper(runes[0]) && startsUpper(prev) && startsUpper(next)
+}
+
+// isOrdinal reports whether number of one or two digits is an ordinal, followed by a lower case word
+// or month like "der 3. deutsche" and "12. März", or by a noun after a determiner like "im 20. Jahrhundert"
+func isOrdinal(form, prev, next string) bool {
+	if len(form) > 2 || next == "" {
+		return false
+	}
+	for _, r := range form {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+
+	if unicode.IsLower([]rune(next)[0]) {
+		return true
+	}
+	if _, ok := months[strings.ToLower(next)]; ok {
+		return true
+	}
+	_, ok := ordinalDeterminers[strings.ToLower(prev)]
+
+	return ok && startsUpper(next)
+}
+
+func startsUpper(form string) bool {
+	return form != "" && unicode.IsUpper([]rune(form)[0])
+}
+
+// prevWord returns word ending before start separated by spaces, empty when punctuation precedes
+func prevWord(runes []rune, start int) string {
+	end := start
+	for end > 0 && unicode.IsSpace(runes[end-1]) {
+		end--
+	}
+	i := end
+	for i > 0 && isWordRune(runes[i-1]) {
+		i--
+	}
+
+	return string(runes[i:end])
+}
+
+// nextWord returns word starting at i after spaces, empty when punctuation follows
+func nextWord(runes []rune, i int) string {
+	for i < len(runes) && unicode.IsSpace(runes[i]) {
+		i++
+	}
+	start := i
+	for i < len(runes) && isWordRune(runes[i]) {
+		i++
+	}
+
+	return string(runes[start:i])
+}
+
+// Split groups tokens into sentences
+func Split(tokens []Token) []Sentence {
+	sentences := make([]Sentence, 0)
+	start := 0
+	for i := 0; i < len(tokens); i++ {
+		if !isTerminal(tokens[i].Form) {
+			continue
+		}
+		end := i + 1
+		for end < len(tokens) && (isTerminal(tokens[end].Form) || isClosing(tokens[end].Form)) {
+			end++
+		}
+		if end < len(tokens) && !startsSentence(tokens[end].Form) {
+			i = end - 1
+			continue
+		}
+		sentences = append(sentences, tokens[start:end])
+		start = end
+		i = end - 1
+	}
+	if start < len(tokens) {
+		sentences = append(sentences, tokens[start:])
+	}
+
+	return sentences
+}
+
+func isTerminal(form string) bool {
+	switch form {
+	case ".", "!", "?", "...", "…":
+		return true
+	}
+
+	return false
+}
+
+func isClosing(form string) bool {
+	switch form {
+	case "\"", "“", "”", "‘", "’", "'", "»", "«", ")", "]":
+		return true
+	}
+
+	return false
+}
+
+func startsSentence(form string) bool {
+	r := []rune(form)[0]
+
+	return !unicode.IsLower(r) && !strings.ContainsRune(",;:)]", r)
+}
+
+// IsWord reports whether token contains a letter
+func (t Token) IsWord() bool {
+	return t.Kind == Word
+}
+
+// Text joins sentence tokens restoring original spacing
+func (s Sentence) Text() string {
+	var b strings.Builder
+	for i, t := range s {
+		b.WriteString(t.Form)
+		if t.SpaceAfter && i < len(s)-1 {
+			b.WriteByte(' ')
+		}
+	}
+
+	return b.String()
+}
+
+// Words returns word and number forms of sentence skipping punctuation
+func (s Sentence) Words(lower bool) []string {
+	words := make([]string, 0, len(s))
+	for _, t := range s {
+		if t.Kind == Punct {
+			continue
+		}
+		form := t.Form
+		if lower {
+			form = strings.ToLower(form)
+		}
+		words = append(words, form)
+	}
+
+	return words
+}
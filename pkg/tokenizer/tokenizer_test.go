@@ -0,0 +1,87 @@
+package tokenizer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"listed abbreviations", "Dr. Müller, Prof. Weber usw.", []string{"Dr.", "Müller", ",", "Prof.", "Weber", "usw."}},
+		{"dotted abbreviations", "z.B. und u.a. heute", []string{"z.B.", "und", "u.a.", "heute"}},
+		{"word abbreviation before number", "nach Art. 5 GG", []string{"nach", "Art.", "5", "GG"}},
+		{"word abbreviation before lower case", "max. zwei, s. unten", []string{"max.", "zwei", ",", "s.", "unten"}},
+		{"word at sentence end", "seine Art. Dann", []string{"seine", "Art", ".", "Dann"}},
+		{"initial", "John F. Kennedy", []string{"John", "F.", "Kennedy"}},
+		{"single letter sentence end", "Sie bekam eine Eins in B. dann", []string{"Sie", "bekam", "eine", "Eins", "in", "B", ".", "dann"}},
+		{"ordinal before month", "am 12. März", []string{"am", "12.", "März"}},
+		{"ordinal before lower case", "der 3. deutsche Titel", []string{"der", "3.", "deutsche", "Titel"}},
+		{"ordinal after determiner", "im 20. Jahrhundert", []string{"im", "20.", "Jahrhundert"}},
+		{"number at sentence end", "stieg auf 12. Dann fiel", []string{"stieg", "auf", "12", ".", "Dann", "fiel"}},
+		{"number at text end", "Es waren 12.", []string{"Es", "waren", "12", "."}},
+		{"long number", "im Jahr 2023. Dann", []string{"im", "Jahr", "2023", ".", "Dann"}},
+		{"decimals", "3,5 Prozent und 1.000 Euro", []string{"3,5", "Prozent", "und", "1.000", "Euro"}},
+		{"hyphenated compounds", "CDU-Chef schreibt E-Mails", []string{"CDU-Chef", "schreibt", "E-Mails"}},
+		{"truncated compound", "Vor- und Nachteile", []string{"Vor-", "und", "Nachteile"}},
+		{"quotes", "„Nein“, sagte er.", []string{"„", "Nein", "“", ",", "sagte", "er", "."}},
+		{"ellipsis", "Und dann...", []string{"Und", "dann", "..."}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]string, 0)
+			for _, token := range Tokens(tt.text) {
+				got = append(got, token.Form)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokens(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"abbreviation inside sentence", "Das sagte Dr. Müller. Dann ging er.", []string{"Das sagte Dr. Müller.", "Dann ging er."}},
+		{"noun like abbreviation", "Das ist seine Art. Dann ging er.", []string{"Das ist seine Art.", "Dann ging er."}},
+		{"name like abbreviation", "Das sagte Max. Dann ging er.", []string{"Das sagte Max.", "Dann ging er."}},
+		{"abbreviation before number", "Laut Art. 5 ist das erlaubt.", []string{"Laut Art. 5 ist das erlaubt."}},
+		{"ordinal inside sentence", "Am 3. Oktober feiern wir. Es ist ein Feiertag.", []string{"Am 3. Oktober feiern wir.", "Es ist ein Feiertag."}},
+		{"number at sentence end", "Der Index stieg auf 12. Dann fiel er.", []string{"Der Index stieg auf 12.", "Dann fiel er."}},
+		{"closing quote after terminal", "Er sagte: „Ja.“ Dann ging er.", []string{"Er sagte: „Ja.“", "Dann ging er."}},
+		{"quote continues sentence", "„Nein!“, rief sie. Alle schwiegen.", []string{"„Nein!“, rief sie.", "Alle schwiegen."}},
+		{"question and exclamation", "Warum? Darum!", []string{"Warum?", "Darum!"}},
+		{"no terminal", "Scholz verteidigt die Zeitenwende", []string{"Scholz verteidigt die Zeitenwende"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]string, 0)
+			for _, sentence := range Tokenize(tt.text) {
+				got = append(got, sentence.Text())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokensStart(t *testing.T) {
+	text := "„Größe“  zählt... z.B. hier"
+	runes := []rune(text)
+	for _, token := range Tokens(text) {
+		end := token.Start + len([]rune(token.Form))
+		if end > len(runes) || string(runes[token.Start:end]) != token.Form {
+			t.Errorf("token %q at rune %d does not match text", token.Form, token.Start)
+		}
+	}
+}